@@ -1,6 +1,6 @@
 module github.com/aisbergg/go-pathlib
 
-go 1.17
+go 1.23
 
 require github.com/spf13/afero v1.4.0
 
@@ -0,0 +1,87 @@
+package pathlib
+
+import (
+	"os"
+)
+
+const (
+	accessRead    = 0x4
+	accessWrite   = 0x2
+	accessExecute = 0x1
+)
+
+// IsReadable returns whether the current process may read the file. For OsFs
+// this is checked with access(2), for other afero filesystems the mode bits
+// are evaluated against the real uid and gids of the process, like access(2)
+// does.
+func (p Path) IsReadable() (bool, error) {
+	return p.access(accessRead)
+}
+
+// IsWritable returns whether the current process may write the file. See
+// IsReadable for details.
+func (p Path) IsWritable() (bool, error) {
+	return p.access(accessWrite)
+}
+
+// IsExecutable returns whether the current process may execute the file, or
+// search it if it is a directory. See IsReadable for details.
+func (p Path) IsExecutable() (bool, error) {
+	return p.access(accessExecute)
+}
+
+func (p Path) access(want uint32) (bool, error) {
+	if isOsFs(p.Fs()) {
+		if allowed, ok, err := osAccess(p.String(), want); ok {
+			return allowed, p.pathError("access", err)
+		}
+	}
+	stat, err := p.StatEx()
+	if err != nil {
+		return false, err
+	}
+	return accessByMode(stat, want), nil
+}
+
+// accessByMode evaluates the mode bits of the file against the real uid and
+// gids of the process. If the filesystem doesn't provide ownership information, the
+// process is assumed to own the file.
+func accessByMode(stat *ExtendedFileInfo, want uint32) bool {
+	perm := uint32(stat.Mode().Perm())
+	shift := uint(6)
+	if stat.Has(StatUID | StatGID) {
+		uid := os.Getuid()
+		if uid == 0 {
+			// root may read and write everything, but only execute files
+			// with at least one execute bit set
+			return want != accessExecute || stat.IsDir() || perm&0o111 != 0
+		}
+		switch {
+		case uid >= 0 && uint32(uid) == stat.UID:
+			shift = 6
+		case inGroup(stat.GID):
+			shift = 3
+		default:
+			shift = 0
+		}
+	}
+	return (perm>>shift)&want == want
+}
+
+// inGroup returns whether the real gid or one of the supplementary groups of
+// the process is the given group.
+func inGroup(gid uint32) bool {
+	if g := os.Getgid(); g >= 0 && uint32(g) == gid {
+		return true
+	}
+	groups, err := os.Getgroups()
+	if err != nil {
+		return false
+	}
+	for _, g := range groups {
+		if g >= 0 && uint32(g) == gid {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,17 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package pathlib
+
+import "os"
+
+// osAccess is not supported on this platform, the mode bits are evaluated
+// instead.
+func osAccess(path string, want uint32) (bool, bool, error) {
+	return false, false, nil
+}
+
+// processUmask returns an empty mask as there is no umask on this platform.
+func processUmask() os.FileMode {
+	return 0
+}
@@ -0,0 +1,89 @@
+package pathlib
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/aisbergg/go-pathlib/internal/testutils"
+	"github.com/spf13/afero"
+)
+
+func TestAccess(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	file := tmpdir.Join("file.txt")
+	require.NoError(file.WriteFile([]byte("hello"), 0o644))
+
+	readable, err := file.IsReadable()
+	require.NoError(err)
+	assert.True(readable)
+	writable, err := file.IsWritable()
+	require.NoError(err)
+	assert.True(writable)
+	executable, err := file.IsExecutable()
+	require.NoError(err)
+	assert.False(executable)
+
+	require.NoError(file.Chmod(0o755))
+	executable, err = file.IsExecutable()
+	require.NoError(err)
+	assert.True(executable)
+
+	if runtime.GOOS != "windows" && os.Getuid() != 0 {
+		require.NoError(file.Chmod(0o000))
+		readable, err = file.IsReadable()
+		require.NoError(err)
+		assert.False(readable)
+	}
+
+	_, err = tmpdir.Join("i_dont_exist").IsReadable()
+	assert.True(errors.Is(err, fs.ErrNotExist))
+}
+
+func TestAccessByMode(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	file := NewPathWithFS(afero.NewMemMapFs(), "/file.txt")
+	require.NoError(file.WriteFile([]byte("hello"), 0o400))
+
+	readable, err := file.IsReadable()
+	require.NoError(err)
+	assert.True(readable)
+	writable, err := file.IsWritable()
+	require.NoError(err)
+	assert.False(writable)
+	executable, err := file.IsExecutable()
+	require.NoError(err)
+	assert.False(executable)
+
+	// the owner class is used when ownership is unknown
+	require.NoError(file.Chmod(0o077))
+	readable, err = file.IsReadable()
+	require.NoError(err)
+	assert.False(readable)
+
+	// with ownership information the matching class is used
+	info, err := file.Stat()
+	require.NoError(err)
+	stat := &ExtendedFileInfo{FileInfo: info, UID: ^uint32(0) - 1, GID: ^uint32(0) - 1, Known: StatUID | StatGID}
+	if os.Getuid() == 0 {
+		assert.True(accessByMode(stat, accessRead))
+		assert.True(accessByMode(stat, accessExecute))
+	} else {
+		assert.True(accessByMode(stat, accessRead|accessWrite|accessExecute))
+	}
+
+	// the group class is picked by the real gid, like access(2) does
+	require.NoError(file.Chmod(0o070))
+	info, err = file.Stat()
+	require.NoError(err)
+	stat = &ExtendedFileInfo{FileInfo: info, UID: ^uint32(0) - 1, GID: uint32(os.Getgid()), Known: StatUID | StatGID}
+	assert.True(accessByMode(stat, accessRead))
+	if os.Getuid() != 0 {
+		stat.GID = ^uint32(0) - 1
+		assert.False(accessByMode(stat, accessRead))
+	}
+}
@@ -0,0 +1,31 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package pathlib
+
+import (
+	"os"
+	"syscall"
+)
+
+// osAccess checks the access permissions with access(2). The second return
+// value reports whether the check could be performed.
+func osAccess(path string, want uint32) (bool, bool, error) {
+	err := syscall.Access(path, want)
+	switch err {
+	case nil:
+		return true, true, nil
+	case syscall.EACCES, syscall.EROFS, syscall.EPERM, syscall.ETXTBSY:
+		return false, true, nil
+	}
+	return false, true, &os.PathError{Op: "access", Path: path, Err: err}
+}
+
+// processUmask returns the file mode creation mask of the process, or an
+// empty mask if it can't be read. Apart from /proc on Linux, the only way to
+// read the umask is to set it and restore it afterwards, which would affect
+// files created concurrently by other goroutines.
+func processUmask() os.FileMode {
+	mask, _ := procUmask()
+	return mask
+}
@@ -0,0 +1,93 @@
+package pathlib
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+)
+
+// AppendFile appends the given data to the file. If the file does not exist,
+// it is created with the given mode or DefaultFileMode. The data of each call
+// is written as one contiguous record, concurrent appends to the same file are
+// never interleaved.
+func (p Path) AppendFile(data []byte, perm ...os.FileMode) error {
+	return p.appendData(data, false, perm...)
+}
+
+// AppendText appends the given text to the file. See AppendFile for details.
+func (p Path) AppendText(text string, perm ...os.FileMode) error {
+	return p.appendData([]byte(text), false, perm...)
+}
+
+// AppendLines appends the given lines to the file, each terminated by a
+// newline. See AppendFile for details.
+func (p Path) AppendLines(lines []string, perm ...os.FileMode) error {
+	return p.appendData(joinLines(lines), false, perm...)
+}
+
+// AppendFileLocked is the same as AppendFile, but holds an exclusive advisory
+// lock on the file while writing. Use it when other processes coordinate their
+// access to the file through locks as well.
+func (p Path) AppendFileLocked(data []byte, perm ...os.FileMode) error {
+	return p.appendData(data, true, perm...)
+}
+
+// AppendTextLocked is the same as AppendText, but holds an exclusive advisory
+// lock on the file while writing.
+func (p Path) AppendTextLocked(text string, perm ...os.FileMode) error {
+	return p.appendData([]byte(text), true, perm...)
+}
+
+// AppendLinesLocked is the same as AppendLines, but holds an exclusive
+// advisory lock on the file while writing.
+func (p Path) AppendLinesLocked(lines []string, perm ...os.FileMode) error {
+	return p.appendData(joinLines(lines), true, perm...)
+}
+
+// appendData writes the data with a single write call to the file opened in
+// append mode. OS files guarantee that such a write is not interleaved with
+// others. Other afero filesystems do not implement O_APPEND atomically, hence
+// writers of the current process are serialized with an in-process lock and
+// the write position is moved to the end of the file once the lock is held.
+func (p Path) appendData(data []byte, lock bool, perm ...os.FileMode) (err error) {
+	if lock && !p.flockable() {
+		release, _, _ := advisoryLocks.acquire(context.Background(), newLockKey(p), true, true)
+		defer release()
+	}
+	defer lockInProcess(p)()
+	file, err := p.OpenFile(os.O_APPEND|os.O_WRONLY|os.O_CREATE, perm...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := file.Close(); err == nil {
+			err = p.pathError("close", cerr)
+		}
+	}()
+
+	if _, isOsFile := file.File.(*os.File); isOsFile {
+		if lock {
+			if _, err := flock(file, true); err != nil {
+				return p.pathError("lock", err)
+			}
+			defer funlock(file)
+		}
+	} else if _, err := file.Seek(0, io.SeekEnd); err != nil {
+		return p.pathError("seek", err)
+	}
+
+	n, err := file.Write(data)
+	if err == nil && n < len(data) {
+		err = io.ErrShortWrite
+	}
+	return p.pathError("write", err)
+}
+
+// joinLines joins the given lines, terminating each of them by a newline.
+func joinLines(lines []string) []byte {
+	if len(lines) == 0 {
+		return []byte{}
+	}
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
@@ -0,0 +1,87 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aisbergg/go-pathlib/internal/testutils"
+	"github.com/spf13/afero"
+)
+
+func TestAppendFile(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	file := tmpdir.Join("file.txt")
+
+	require.NoError(file.AppendFile([]byte("hello")))
+	require.NoError(file.AppendText(" world\n"))
+	require.NoError(file.AppendLines([]string{"one", "two"}))
+	require.NoError(file.AppendLines(nil))
+	require.NoError(file.AppendTextLocked("three\n"))
+
+	content, err := file.ReadFile()
+	require.NoError(err)
+	assert.Equal("hello world\none\ntwo\nthree\n", string(content))
+
+	info, err := file.Stat()
+	require.NoError(err)
+	assert.Equal(DefaultFileMode, info.Mode()&os.ModePerm)
+}
+
+func TestAppendFileMode(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	file := tmpdir.Join("file.txt")
+
+	require.NoError(file.AppendFile([]byte("hello"), 0o600))
+	info, err := file.Stat()
+	require.NoError(err)
+	assert.Equal(os.FileMode(0o600), info.Mode()&os.ModePerm)
+}
+
+func testConcurrentAppends(t *testing.T, file Path, locked bool) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	const writers, records = 8, 50
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for r := 0; r < records; r++ {
+				record := fmt.Sprintf("%d-%d-%s", w, r, strings.Repeat("x", 100))
+				var err error
+				if locked {
+					err = file.AppendLinesLocked([]string{record, record})
+				} else {
+					err = file.AppendLines([]string{record, record})
+				}
+				assert.NoError(err)
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	lines, err := file.Head(2 * writers * records * 2)
+	require.NoError(err)
+	require.Equal(2*writers*records, len(lines))
+	for i := 0; i < len(lines); i += 2 {
+		assert.Equal(lines[i], lines[i+1], "record at line %d was interleaved", i)
+	}
+}
+
+func TestAppendConcurrent(t *testing.T) {
+	_, _, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	for _, locked := range []bool{false, true} {
+		t.Run(fmt.Sprintf("OsFs/locked=%v", locked), func(t *testing.T) {
+			testConcurrentAppends(t, tmpdir.Join(fmt.Sprintf("file-%v.txt", locked)), locked)
+		})
+		t.Run(fmt.Sprintf("MemMapFs/locked=%v", locked), func(t *testing.T) {
+			testConcurrentAppends(t, NewPathWithFS(afero.NewMemMapFs(), "/file.txt"), locked)
+		})
+	}
+}
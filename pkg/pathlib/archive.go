@@ -0,0 +1,406 @@
+package pathlib
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ArchiveFormat is the format of an archive created by ArchiveTo.
+type ArchiveFormat int
+
+const (
+	// ArchiveTar is an uncompressed tar archive.
+	ArchiveTar ArchiveFormat = iota
+	// ArchiveTarGz is a gzip compressed tar archive.
+	ArchiveTarGz
+	// ArchiveZip is a zip archive with deflate compressed files.
+	ArchiveZip
+)
+
+// String returns the common file extension of the format, without the leading
+// dot.
+func (f ArchiveFormat) String() string {
+	switch f {
+	case ArchiveTar:
+		return "tar"
+	case ArchiveTarGz:
+		return "tar.gz"
+	case ArchiveZip:
+		return "zip"
+	default:
+		return fmt.Sprintf("ArchiveFormat(%d)", int(f))
+	}
+}
+
+// ArchiveOpts is the struct that defines how an archive is created.
+type ArchiveOpts struct {
+	// Include restricts the archive to the objects matching any of the
+	// patterns, if not empty. Directories are archived only as far as they
+	// contain included objects then. Patterns are matched with path.Match
+	// against the slash separated path relative to the archived path; patterns
+	// without a slash are matched against the name only.
+	Include []string
+
+	// Exclude omits the objects matching any of the patterns, including
+	// everything below excluded directories. The patterns are matched like the
+	// ones of Include.
+	Exclude []string
+
+	// PreserveSymlinks specifies that symlinks are stored as such. If false,
+	// symlinks are followed and their targets are archived instead; a symlink
+	// to a directory containing it fails the archiving with ErrSymlinkLoop.
+	PreserveSymlinks bool
+
+	// Reproducible specifies that archives are byte-identical as long as the
+	// archived content is the same: all modification times are set to ModTime,
+	// the owner is set to root, and the permission bits are normalized to 0755
+	// for directories and executables, to 0777 for symlinks and to 0644 for all
+	// other files.
+	Reproducible bool
+
+	// ModTime is the modification time of all entries in reproducible mode.
+	ModTime time.Time
+}
+
+// DefaultArchiveOpts returns the default ArchiveOpts struct used when creating
+// archives.
+func DefaultArchiveOpts() *ArchiveOpts {
+	return &ArchiveOpts{
+		Include:          nil,
+		Exclude:          nil,
+		PreserveSymlinks: true,
+		Reproducible:     false,
+		// the earliest time representable in zip archives
+		ModTime: time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// archiveEntry is an object to be stored in an archive.
+type archiveEntry struct {
+	path Path
+	// name is the slash separated path relative to the archived path
+	name string
+	info os.FileInfo
+}
+
+// ArchiveTo creates an archive of the given format at dst, containing the tree
+// below the path (or the path itself if it is not a directory). The entries
+// are named relative to the path and are stored sorted by name. The archive
+// itself is skipped if it is located within the tree. Objects other than
+// regular files, directories and symlinks, e.g. named pipes, are skipped.
+//
+// The archive is written to a temporary file first, which replaces dst once
+// it is complete.
+func (p Path) ArchiveTo(dst Path, format ArchiveFormat, opts *ArchiveOpts) error {
+	if opts == nil {
+		return p.pathError("archive", fmt.Errorf("opts can't be nil"))
+	}
+	if format != ArchiveTar && format != ArchiveTarGz && format != ArchiveZip {
+		return p.pathError("archive", fmt.Errorf("unsupported archive format: %s", format))
+	}
+	for _, pattern := range append(append([]string{}, opts.Include...), opts.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return p.pathError("archive", fmt.Errorf("%w: %q", err, pattern))
+		}
+	}
+
+	entries, err := p.archiveEntries(dst, opts)
+	if err != nil {
+		return err
+	}
+	return dst.writeAtomic(dst.DefaultFileMode, func(w io.Writer) error {
+		var err error
+		switch format {
+		case ArchiveTar:
+			err = writeTar(w, entries, opts)
+		case ArchiveTarGz:
+			gz := gzip.NewWriter(w)
+			if err = writeTar(gz, entries, opts); err == nil {
+				err = gz.Close()
+			}
+		case ArchiveZip:
+			err = writeZip(w, entries, opts)
+		}
+		return dst.pathError("archive", err)
+	})
+}
+
+// archiveEntries collects the objects to be archived, sorted by name.
+func (p Path) archiveEntries(dst Path, opts *ArchiveOpts) ([]archiveEntry, error) {
+	info, err := p.lstatIfPossible()
+	if err != nil {
+		return nil, err
+	}
+	if !opts.PreserveSymlinks && IsSymlink(info.Mode()) {
+		if info, err = p.Stat(); err != nil {
+			return nil, err
+		}
+	}
+	if !info.IsDir() {
+		return []archiveEntry{{path: p, name: p.Name(), info: info}}, nil
+	}
+
+	dstName := dst.Clean().String()
+	var entries []archiveEntry
+	// ancestors holds the identities of the directories from p down to dir,
+	// a followed symlink to one of them would be descended into endlessly
+	var collect func(dir Path, ancestors []FileID) error
+	collect = func(dir Path, ancestors []FileID) error {
+		dirEntries, err := dir.Entries()
+		if err != nil {
+			return err
+		}
+		for _, dirEntry := range dirEntries {
+			child := dirEntry.Path()
+			var info os.FileInfo
+			if !opts.PreserveSymlinks && IsSymlink(dirEntry.Type()) {
+				info, err = child.Stat()
+			} else {
+				info, err = dirEntry.Info()
+			}
+			if err != nil {
+				return err
+			}
+			if child.Clean().String() == dstName {
+				continue
+			}
+			switch TypeOf(info.Mode()) {
+			case FileTypeRegular, FileTypeDir, FileTypeSymlink:
+			default:
+				continue
+			}
+			rel, err := child.RelativeTo(p.String())
+			if err != nil {
+				return err
+			}
+			name := filepath.ToSlash(rel.String())
+			if matchesAnyPrefix(opts.Exclude, name) {
+				continue
+			}
+			entries = append(entries, archiveEntry{path: child, name: name, info: info})
+			if !info.IsDir() {
+				continue
+			}
+			childAncestors := ancestors[:len(ancestors):len(ancestors)]
+			if id, ok := fileIDFromInfo(info); ok {
+				for _, ancestor := range ancestors {
+					if ancestor == id {
+						return child.pathError("archive", ErrSymlinkLoop)
+					}
+				}
+				childAncestors = append(childAncestors, id)
+			}
+			if err := collect(child, childAncestors); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	var ancestors []FileID
+	if id, ok := fileIDFromInfo(info); ok {
+		ancestors = append(ancestors, id)
+	}
+	if err := collect(p, ancestors); err != nil {
+		return nil, err
+	}
+
+	if len(opts.Include) > 0 {
+		entries = includeEntries(entries, opts.Include)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].name < entries[j].name
+	})
+	return entries, nil
+}
+
+// includeEntries returns the non-directory entries matching any of the
+// patterns together with their parent directories.
+func includeEntries(entries []archiveEntry, patterns []string) []archiveEntry {
+	dirs := map[string]bool{}
+	var included []archiveEntry
+	for _, entry := range entries {
+		if entry.info.IsDir() || !matchesAny(patterns, entry.name) {
+			continue
+		}
+		included = append(included, entry)
+		for dir := path.Dir(entry.name); dir != "."; dir = path.Dir(dir) {
+			dirs[dir] = true
+		}
+	}
+	for _, entry := range entries {
+		if entry.info.IsDir() && dirs[entry.name] {
+			included = append(included, entry)
+		}
+	}
+	return included
+}
+
+// matchesAny returns whether the slash separated name matches any of the
+// patterns. Patterns without a slash are matched against the last element.
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		subject := name
+		if !strings.Contains(pattern, "/") {
+			subject = path.Base(name)
+		}
+		if matched, _ := path.Match(pattern, subject); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAnyPrefix returns whether the name or any of its parent directories
+// matches any of the patterns.
+func matchesAnyPrefix(patterns []string, name string) bool {
+	for prefix := name; prefix != "."; prefix = path.Dir(prefix) {
+		if matchesAny(patterns, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// archiveMeta returns the permission bits, modification time and owner of the
+// entry as stored in the archive.
+func archiveMeta(entry archiveEntry, opts *ArchiveOpts) (mode os.FileMode, modTime time.Time, uid, gid int) {
+	if opts.Reproducible {
+		switch {
+		case IsSymlink(entry.info.Mode()):
+			mode = 0o777
+		case entry.info.IsDir(), entry.info.Mode().Perm()&0o111 != 0:
+			mode = 0o755
+		default:
+			mode = 0o644
+		}
+		return mode, opts.ModTime, 0, 0
+	}
+
+	mode = entry.info.Mode() & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky)
+	if x := NewExtendedFileInfo(entry.info); x.Has(StatUID | StatGID) {
+		uid, gid = int(x.UID), int(x.GID)
+	}
+	return mode, entry.info.ModTime(), uid, gid
+}
+
+// openEntry opens the content of a regular file entry, or returns the target
+// of a symlink entry as content.
+func openEntry(entry archiveEntry) (io.ReadCloser, error) {
+	if IsSymlink(entry.info.Mode()) {
+		target, err := entry.path.Readlink()
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(strings.NewReader(filepath.ToSlash(target.String()))), nil
+	}
+	return entry.path.Open()
+}
+
+func writeTar(w io.Writer, entries []archiveEntry, opts *ArchiveOpts) error {
+	tw := tar.NewWriter(w)
+	for _, entry := range entries {
+		mode, modTime, uid, gid := archiveMeta(entry, opts)
+		header := &tar.Header{
+			Name:    entry.name,
+			Mode:    tarMode(mode),
+			ModTime: modTime,
+			Uid:     uid,
+			Gid:     gid,
+		}
+		switch TypeOf(entry.info.Mode()) {
+		case FileTypeDir:
+			header.Typeflag = tar.TypeDir
+			header.Name += "/"
+		case FileTypeSymlink:
+			target, err := entry.path.Readlink()
+			if err != nil {
+				return err
+			}
+			header.Typeflag = tar.TypeSymlink
+			header.Linkname = filepath.ToSlash(target.String())
+		default:
+			header.Typeflag = tar.TypeReg
+			header.Size = entry.info.Size()
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		file, err := entry.path.Open()
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(tw, file)
+		file.Close()
+		if err != nil {
+			return entry.path.pathError("read", err)
+		}
+	}
+	return tw.Close()
+}
+
+// tarMode converts the permission bits to the mode of a tar header.
+func tarMode(mode os.FileMode) int64 {
+	m := int64(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		m |= 0o4000
+	}
+	if mode&os.ModeSetgid != 0 {
+		m |= 0o2000
+	}
+	if mode&os.ModeSticky != 0 {
+		m |= 0o1000
+	}
+	return m
+}
+
+func writeZip(w io.Writer, entries []archiveEntry, opts *ArchiveOpts) error {
+	zw := zip.NewWriter(w)
+	for _, entry := range entries {
+		mode, modTime, _, _ := archiveMeta(entry, opts)
+		header := &zip.FileHeader{
+			Name:     entry.name,
+			Modified: modTime,
+			Method:   zip.Deflate,
+		}
+		switch TypeOf(entry.info.Mode()) {
+		case FileTypeDir:
+			header.Name += "/"
+			header.Method = zip.Store
+			mode |= os.ModeDir
+		case FileTypeSymlink:
+			// the target is stored as content, like Info-ZIP does
+			header.Method = zip.Store
+			mode |= os.ModeSymlink
+		}
+		header.SetMode(mode)
+		fw, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		if entry.info.IsDir() {
+			continue
+		}
+		content, err := openEntry(entry)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(fw, content)
+		content.Close()
+		if err != nil {
+			return entry.path.pathError("read", err)
+		}
+	}
+	return zw.Close()
+}
@@ -0,0 +1,208 @@
+package pathlib
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aisbergg/go-pathlib/internal/testutils"
+	"github.com/spf13/afero"
+)
+
+// readTar returns the headers and contents of the archive, keyed by name.
+func readTar(t *testing.T, r io.Reader) ([]*tar.Header, map[string]string) {
+	require := testutils.NewRequire(t)
+	tr := tar.NewReader(r)
+	var headers []*tar.Header
+	contents := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(err)
+		content, err := io.ReadAll(tr)
+		require.NoError(err)
+		headers = append(headers, header)
+		contents[header.Name] = string(content)
+	}
+	return headers, contents
+}
+
+func tarNames(headers []*tar.Header) []string {
+	names := make([]string, len(headers))
+	for i, header := range headers {
+		names[i] = header.Name
+	}
+	return names
+}
+
+func TestArchiveToTar(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	root := tmpdir.Join("root")
+	require.NoError(root.Mkdir())
+	require.NoError(TwoFilesAtRootTwoInSubdir(root))
+	require.NoError(root.Join("link").Symlink(NewPath("file0.txt")))
+	dst := tmpdir.Join("out.tar")
+
+	require.NoError(root.ArchiveTo(dst, ArchiveTar, DefaultArchiveOpts()))
+	file, err := dst.Open()
+	require.NoError(err)
+	defer file.Close()
+	headers, contents := readTar(t, file)
+	assert.Equal([]string{"file0.txt", "file1.txt", "link", "subdir/", "subdir/file0.txt", "subdir/file1.txt"}, tarNames(headers))
+	assert.Equal("file0 contents", contents["subdir/file0.txt"])
+	assert.Equal(byte(tar.TypeSymlink), headers[2].Typeflag)
+	assert.Equal("file0.txt", headers[2].Linkname)
+	assert.Equal(byte(tar.TypeDir), headers[3].Typeflag)
+
+	// followed symlinks are stored as regular files
+	opts := DefaultArchiveOpts()
+	opts.PreserveSymlinks = false
+	require.NoError(root.ArchiveTo(dst, ArchiveTar, opts))
+	file, err = dst.Open()
+	require.NoError(err)
+	defer file.Close()
+	headers, contents = readTar(t, file)
+	assert.Equal(byte(tar.TypeReg), headers[2].Typeflag)
+	assert.Equal("file0 contents", contents["link"])
+}
+
+func TestArchiveToSymlinkLoop(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	root := tmpdir.Join("root")
+	require.NoError(root.Mkdir())
+	require.NoError(TwoFilesAtRootTwoInSubdir(root))
+	require.NoError(root.Join("dirlink").SymlinkStr("subdir"))
+	dst := tmpdir.Join("out.tar")
+	opts := DefaultArchiveOpts()
+	opts.PreserveSymlinks = false
+
+	// a symlink to a directory next to it is followed
+	require.NoError(root.ArchiveTo(dst, ArchiveTar, opts))
+	file, err := dst.Open()
+	require.NoError(err)
+	defer file.Close()
+	headers, _ := readTar(t, file)
+	assert.Equal([]string{"dirlink/", "dirlink/file0.txt", "dirlink/file1.txt", "file0.txt", "file1.txt", "subdir/", "subdir/file0.txt", "subdir/file1.txt"}, tarNames(headers))
+
+	// a symlink to a directory above it is not
+	require.NoError(root.Join("subdir", "loop").SymlinkStr(".."))
+	err = root.ArchiveTo(tmpdir.Join("loop.tar"), ArchiveTar, opts)
+	assert.True(errors.Is(err, ErrSymlinkLoop))
+	exists, err := tmpdir.Join("loop.tar").Exists()
+	require.NoError(err)
+	assert.False(exists)
+}
+
+func TestArchiveToFilters(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	root := tempDirForTest(t, afero.NewMemMapFs())
+	require.NoError(TwoFilesAtRootTwoInSubdir(root))
+	require.NoError(root.Join("subdir", "notes.md").WriteFile([]byte("notes")))
+	dst := root.Join("out.tar.gz")
+
+	opts := DefaultArchiveOpts()
+	opts.Include = []string{"*.md", "file1.txt"}
+	opts.Exclude = []string{"subdir/file1.txt"}
+	require.NoError(root.ArchiveTo(dst, ArchiveTarGz, opts))
+	file, err := dst.Open()
+	require.NoError(err)
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	require.NoError(err)
+	headers, _ := readTar(t, gz)
+	assert.Equal([]string{"file1.txt", "subdir/", "subdir/notes.md"}, tarNames(headers))
+
+	opts = DefaultArchiveOpts()
+	opts.Exclude = []string{"subdir"}
+	require.NoError(root.ArchiveTo(dst, ArchiveTar, opts))
+	file, err = dst.Open()
+	require.NoError(err)
+	defer file.Close()
+	headers, _ = readTar(t, file)
+	assert.Equal([]string{"file0.txt", "file1.txt"}, tarNames(headers))
+
+	opts.Exclude = []string{"["}
+	assert.Error(root.ArchiveTo(dst, ArchiveTar, opts))
+	assert.Error(root.ArchiveTo(dst, ArchiveFormat(42), DefaultArchiveOpts()))
+	assert.Error(root.ArchiveTo(dst, ArchiveTar, nil))
+}
+
+func TestArchiveToZip(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	root := tmpdir.Join("root")
+	require.NoError(root.Mkdir())
+	require.NoError(TwoFilesAtRootTwoInSubdir(root))
+	require.NoError(root.Join("link").Symlink(NewPath("file0.txt")))
+	dst := tmpdir.Join("out.zip")
+
+	require.NoError(root.ArchiveTo(dst, ArchiveZip, DefaultArchiveOpts()))
+	reader, err := zip.OpenReader(dst.String())
+	require.NoError(err)
+	defer reader.Close()
+	var names []string
+	for _, f := range reader.File {
+		names = append(names, f.Name)
+	}
+	assert.Equal([]string{"file0.txt", "file1.txt", "link", "subdir/", "subdir/file0.txt", "subdir/file1.txt"}, names)
+	assert.True(IsSymlink(reader.File[2].Mode()))
+	assert.True(reader.File[3].Mode().IsDir())
+	content, err := reader.File[4].Open()
+	require.NoError(err)
+	defer content.Close()
+	data, err := io.ReadAll(content)
+	require.NoError(err)
+	assert.Equal("file0 contents", string(data))
+}
+
+func TestArchiveToReproducible(t *testing.T) {
+	for _, format := range []ArchiveFormat{ArchiveTar, ArchiveTarGz, ArchiveZip} {
+		assert := testutils.NewAssert(t)
+		require := testutils.NewRequire(t)
+		var archives [][]byte
+		for i := 0; i < 2; i++ {
+			root := tempDirForTest(t, afero.NewMemMapFs())
+			require.NoError(TwoFilesAtRootTwoInSubdir(root))
+			mtime := time.Now().Add(time.Duration(i) * time.Hour)
+			require.NoError(root.Join("file0.txt").Chtimes(mtime, mtime))
+			require.NoError(root.Join("file1.txt").Chmod(os.FileMode(0o600 + 0o40*i)))
+			dst := root.Parent().Join(root.Name() + "." + format.String())
+
+			opts := DefaultArchiveOpts()
+			opts.Reproducible = true
+			require.NoError(root.ArchiveTo(dst, format, opts))
+			data, err := dst.ReadFile()
+			require.NoError(err)
+			archives = append(archives, data)
+		}
+		assert.True(bytes.Equal(archives[0], archives[1]), format.String())
+	}
+}
+
+func TestArchiveToSingleFile(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	dir := tempDirForTest(t, afero.NewMemMapFs())
+	file := dir.Join("file.txt")
+	require.NoError(file.WriteFile([]byte("hello")))
+	dst := dir.Join("file.tar")
+
+	require.NoError(file.ArchiveTo(dst, ArchiveTar, DefaultArchiveOpts()))
+	handle, err := dst.Open()
+	require.NoError(err)
+	defer handle.Close()
+	headers, contents := readTar(t, handle)
+	assert.Equal([]string{"file.txt"}, tarNames(headers))
+	assert.Equal("hello", contents["file.txt"])
+}
@@ -0,0 +1,232 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// permission bits in the notation of chmod(1)
+const (
+	modeSetuid = 0o4000
+	modeSetgid = 0o2000
+	modeSticky = 0o1000
+)
+
+// ChmodSymbolic changes the mode of the file according to the given
+// expression in the notation of chmod(1), e.g. "u+x,go-w", "a=rX" or "0644".
+// Symlinks are followed. As with chmod(1), bits set in the umask of the
+// process are not affected by clauses without an explicit "who" part ("+x").
+//
+// The umask is only known on Linux, where it is read from /proc. On other
+// platforms, or if /proc isn't mounted, the umask is taken to be empty, so
+// "+x" acts like "a+x". Use an explicit "who" part to be independent of it.
+func (p Path) ChmodSymbolic(expr string) error {
+	info, err := p.Stat()
+	if err != nil {
+		return err
+	}
+	mode, err := applySymbolicMode(info.Mode(), expr, processUmask())
+	if err != nil {
+		return p.pathError("chmod", err)
+	}
+	return p.Chmod(mode)
+}
+
+// ChmodAll changes the mode of the path and, if it is a directory, of
+// everything below it. Files and directories get separate modes, each given
+// as an expression for ChmodSymbolic; an empty expression leaves the
+// respective modes unchanged. Symlinks are neither followed nor changed.
+func (p Path) ChmodAll(fileExpr, dirExpr string) error {
+	umask := processUmask()
+	chmod := func(path Path, info os.FileInfo) error {
+		expr := fileExpr
+		if info.IsDir() {
+			expr = dirExpr
+		}
+		if expr == "" || IsSymlink(info.Mode()) {
+			return nil
+		}
+		mode, err := applySymbolicMode(info.Mode(), expr, umask)
+		if err != nil {
+			return path.pathError("chmod", err)
+		}
+		return path.Chmod(mode)
+	}
+
+	info, err := p.lstatIfPossible()
+	if err != nil {
+		return err
+	}
+	if err := chmod(p, info); err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return nil
+	}
+	walk, err := NewWalk(p)
+	if err != nil {
+		return err
+	}
+	return walk.Walk(func(path Path, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		return chmod(path, info)
+	})
+}
+
+// applySymbolicMode applies the chmod(1) expression to the given mode. The
+// expression is either an octal number or a comma separated list of clauses
+// of the form [ugoa]*([-+=]([rwxXst]*|[ugo]))+.
+func applySymbolicMode(mode os.FileMode, expr string, umask os.FileMode) (os.FileMode, error) {
+	if expr == "" {
+		return mode, fmt.Errorf("%w: empty mode", ErrInvalidMode)
+	}
+	if isOctal(expr) {
+		perm, err := strconv.ParseUint(expr, 8, 32)
+		if err != nil || perm > 0o7777 {
+			return mode, fmt.Errorf("%w: %s", ErrInvalidMode, expr)
+		}
+		return withPermBits(mode, uint32(perm)), nil
+	}
+
+	perm := permBits(mode)
+	isDir := mode.IsDir()
+	mask := uint32(umask & os.ModePerm)
+	for _, clause := range strings.Split(expr, ",") {
+		// who
+		i := 0
+		var who uint32
+		for ; i < len(clause) && strings.IndexByte("ugoa", clause[i]) >= 0; i++ {
+			switch clause[i] {
+			case 'u':
+				who |= modeSetuid | 0o700
+			case 'g':
+				who |= modeSetgid | 0o070
+			case 'o':
+				who |= modeSticky | 0o007
+			case 'a':
+				who |= 0o7777
+			}
+		}
+		explicitWho := who != 0
+		if !explicitWho {
+			who = 0o7777
+		}
+		if i >= len(clause) {
+			return mode, fmt.Errorf("%w: missing operator in %q", ErrInvalidMode, clause)
+		}
+
+		// one or more operations
+		for i < len(clause) {
+			op := clause[i]
+			if op != '+' && op != '-' && op != '=' {
+				return mode, fmt.Errorf("%w: invalid operator %q in %q", ErrInvalidMode, op, clause)
+			}
+			i++
+
+			var bits uint32
+			if i < len(clause) && strings.IndexByte("ugo", clause[i]) >= 0 {
+				// copy the permissions of another class
+				var v uint32
+				switch clause[i] {
+				case 'u':
+					v = (perm >> 6) & 0o7
+				case 'g':
+					v = (perm >> 3) & 0o7
+				case 'o':
+					v = perm & 0o7
+				}
+				bits = v<<6 | v<<3 | v
+				i++
+			} else {
+				for ; i < len(clause) && strings.IndexByte("rwxXst", clause[i]) >= 0; i++ {
+					switch clause[i] {
+					case 'r':
+						bits |= 0o444
+					case 'w':
+						bits |= 0o222
+					case 'x':
+						bits |= 0o111
+					case 'X':
+						if isDir || perm&0o111 != 0 {
+							bits |= 0o111
+						}
+					case 's':
+						bits |= modeSetuid | modeSetgid
+					case 't':
+						bits |= modeSticky
+					}
+				}
+			}
+
+			bits &= who
+			if !explicitWho {
+				bits &^= mask
+			}
+			switch op {
+			case '+':
+				perm |= bits
+			case '-':
+				perm &^= bits
+			case '=':
+				clearBits := who
+				if !explicitWho {
+					clearBits &^= mask
+				}
+				// like chmod(1), keep setuid and setgid of directories
+				// unless they are cleared explicitly
+				if isDir {
+					clearBits &^= modeSetuid | modeSetgid
+				}
+				perm = perm&^clearBits | bits
+			}
+		}
+	}
+	return withPermBits(mode, perm), nil
+}
+
+// isOctal returns whether s consists of octal digits only.
+func isOctal(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '7' {
+			return false
+		}
+	}
+	return true
+}
+
+// permBits returns the permission bits of the os.FileMode in the notation of
+// chmod(1).
+func permBits(mode os.FileMode) uint32 {
+	perm := uint32(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		perm |= modeSetuid
+	}
+	if mode&os.ModeSetgid != 0 {
+		perm |= modeSetgid
+	}
+	if mode&os.ModeSticky != 0 {
+		perm |= modeSticky
+	}
+	return perm
+}
+
+// withPermBits returns the os.FileMode with its permission bits replaced by
+// the given bits in the notation of chmod(1).
+func withPermBits(mode os.FileMode, perm uint32) os.FileMode {
+	mode &^= os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky
+	mode |= os.FileMode(perm) & os.ModePerm
+	if perm&modeSetuid != 0 {
+		mode |= os.ModeSetuid
+	}
+	if perm&modeSetgid != 0 {
+		mode |= os.ModeSetgid
+	}
+	if perm&modeSticky != 0 {
+		mode |= os.ModeSticky
+	}
+	return mode
+}
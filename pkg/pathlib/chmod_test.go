@@ -0,0 +1,101 @@
+package pathlib
+
+import (
+	"os"
+	"testing"
+
+	"github.com/aisbergg/go-pathlib/internal/testutils"
+	"github.com/spf13/afero"
+)
+
+func TestApplySymbolicMode(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	tests := []struct {
+		mode     os.FileMode
+		expr     string
+		umask    os.FileMode
+		expected os.FileMode
+	}{
+		{0o644, "u+x", 0o022, 0o744},
+		{0o777, "go-w", 0o022, 0o755},
+		{0o644, "u+x,go-w", 0o022, 0o744},
+		{0o644, "+x", 0o022, 0o755},
+		{0o644, "+x", 0o077, 0o744},
+		{0o777, "-w", 0o022, 0o577},
+		{0o777, "a-w", 0o022, 0o555},
+		{0o600, "a=r", 0o022, 0o444},
+		{0o600, "=rw", 0o022, 0o644},
+		{0o640, "o=g", 0o022, 0o644},
+		{0o750, "go=u", 0o000, 0o777},
+		{0o640, "u=rw,g=r,o=", 0o022, 0o640},
+		{0o644, "u+rwx-w", 0o022, 0o544},
+		{0o644, "a+X", 0o022, 0o644},
+		{0o744, "a+X", 0o022, 0o755},
+		{os.ModeDir | 0o700, "a+X", 0o022, os.ModeDir | 0o711},
+		{os.ModeDir | 0o755, "+t", 0o022, os.ModeDir | os.ModeSticky | 0o755},
+		{0o755, "u+s", 0o022, os.ModeSetuid | 0o755},
+		{0o755, "g+s", 0o022, os.ModeSetgid | 0o755},
+		{0o755, "o+s", 0o022, 0o755},
+		{os.ModeSetuid | 0o755, "u-s", 0o022, 0o755},
+		{os.ModeDir | os.ModeSetgid | 0o755, "g=rx", 0o022, os.ModeDir | os.ModeSetgid | 0o755},
+		{0o644, "600", 0o022, 0o600},
+		{0o644, "4755", 0o022, os.ModeSetuid | 0o755},
+	}
+	for _, test := range tests {
+		mode, err := applySymbolicMode(test.mode, test.expr, test.umask)
+		assert.NoError(err, "expression %q", test.expr)
+		assert.Equal(test.expected, mode, "expression %q on %v", test.expr, test.mode)
+	}
+
+	for _, expr := range []string{"", "u", "u*x", "u+q", "17777", "u+x,", "z+x"} {
+		_, err := applySymbolicMode(0o644, expr, 0o022)
+		assert.EqualError(ErrInvalidMode, err, "expression %q", expr)
+	}
+}
+
+func TestChmodSymbolic(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	file := tmpdir.Join("file.txt")
+	require.NoError(file.WriteFile([]byte("hello"), 0o644))
+
+	require.NoError(file.ChmodSymbolic("u+x,go-r"))
+	info, err := file.Stat()
+	require.NoError(err)
+	assert.Equal(os.FileMode(0o700), info.Mode().Perm())
+
+	assert.EqualError(ErrInvalidMode, file.ChmodSymbolic("u+q"))
+}
+
+func TestChmodAll(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	root := NewPathWithFS(afero.NewMemMapFs(), "/root")
+	require.NoError(root.MkdirAll(0o700))
+	require.NoError(TwoFilesAtRootTwoInSubdir(root))
+	require.NoError(root.Join("subdir", "file0.txt").Chmod(0o700))
+
+	require.NoError(root.ChmodAll("a=rX,u+w", "0755"))
+	for _, test := range []struct {
+		path     Path
+		expected os.FileMode
+	}{
+		{root, 0o755},
+		{root.Join("subdir"), 0o755},
+		{root.Join("file0.txt"), 0o644},
+		{root.Join("subdir", "file0.txt"), 0o755},
+	} {
+		info, err := test.path.Stat()
+		require.NoError(err)
+		assert.Equal(test.expected, info.Mode().Perm(), "path %s", test.path)
+	}
+
+	// empty expressions leave the modes unchanged
+	require.NoError(root.ChmodAll("", "0700"))
+	info, err := root.Join("file0.txt").Stat()
+	require.NoError(err)
+	assert.Equal(os.FileMode(0o644), info.Mode().Perm())
+	info, err = root.Join("subdir").Stat()
+	require.NoError(err)
+	assert.Equal(os.FileMode(0o700), info.Mode().Perm())
+}
@@ -0,0 +1,288 @@
+package pathlib
+
+import (
+	"bufio"
+	"encoding/gob"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/afero"
+)
+
+// EncodeOpts is the struct that defines how structured content is written to
+// a file.
+type EncodeOpts struct {
+	// Indent is the string used to indent nested elements, e.g. "  ". The
+	// output is compact if it is empty. Codecs without a textual
+	// representation ignore it.
+	Indent string
+
+	// Atomic specifies that the content is written to a temporary file in the
+	// same directory first, which then replaces the file. Readers never see a
+	// partially written file then. The permission bits of an existing file are
+	// retained.
+	Atomic bool
+
+	// Mode is the file mode a new file is created with. A value of 0 stands
+	// for the DefaultFileMode of the path.
+	Mode os.FileMode
+}
+
+// DefaultEncodeOpts returns the default EncodeOpts struct used when writing
+// structured content.
+func DefaultEncodeOpts() *EncodeOpts {
+	return &EncodeOpts{
+		Indent: "",
+		Atomic: true,
+		Mode:   0,
+	}
+}
+
+// Codec encodes and decodes values to and from a serialization format.
+type Codec interface {
+	// Encode writes the encoding of v to w.
+	Encode(w io.Writer, v any, opts *EncodeOpts) error
+	// Decode reads the next encoded value from r and stores it in the value
+	// pointed to by v.
+	Decode(r io.Reader, v any) error
+}
+
+var (
+	// JSONCodec encodes values with encoding/json.
+	JSONCodec Codec = jsonCodec{}
+	// XMLCodec encodes values with encoding/xml.
+	XMLCodec Codec = xmlCodec{}
+	// GobCodec encodes values with encoding/gob.
+	GobCodec Codec = gobCodec{}
+)
+
+type jsonCodec struct{}
+
+func (jsonCodec) Encode(w io.Writer, v any, opts *EncodeOpts) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", opts.Indent)
+	return encoder.Encode(v)
+}
+
+func (jsonCodec) Decode(r io.Reader, v any) error {
+	return json.NewDecoder(r).Decode(v)
+}
+
+type xmlCodec struct{}
+
+func (xmlCodec) Encode(w io.Writer, v any, opts *EncodeOpts) error {
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", opts.Indent)
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+	if opts.Indent != "" {
+		_, err := io.WriteString(w, "\n")
+		return err
+	}
+	return nil
+}
+
+func (xmlCodec) Decode(r io.Reader, v any) error {
+	return xml.NewDecoder(r).Decode(v)
+}
+
+type gobCodec struct{}
+
+func (gobCodec) Encode(w io.Writer, v any, opts *EncodeOpts) error {
+	return gob.NewEncoder(w).Encode(v)
+}
+
+func (gobCodec) Decode(r io.Reader, v any) error {
+	return gob.NewDecoder(r).Decode(v)
+}
+
+// -----------------------------------------------------------------------------
+//
+// Registry
+//
+// -----------------------------------------------------------------------------
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[string]Codec{
+		".json": JSONCodec,
+		".xml":  XMLCodec,
+		".gob":  GobCodec,
+	}
+)
+
+// RegisterCodec registers the codec for files with the given suffix, like
+// ".yaml", replacing any codec registered before. Suffixes are matched case
+// insensitively. The codecs for ".json", ".xml" and ".gob" are registered by
+// default.
+func RegisterCodec(suffix string, codec Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	codecs[strings.ToLower(suffix)] = codec
+}
+
+// CodecFor returns the codec registered for the given suffix.
+func CodecFor(suffix string) (Codec, bool) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	codec, ok := codecs[strings.ToLower(suffix)]
+	return codec, ok
+}
+
+// codecFor returns the codec registered for the suffix of the path.
+func (p Path) codecFor(op string) (Codec, error) {
+	codec, ok := CodecFor(p.Suffix())
+	if !ok {
+		return nil, p.pathError(op, fmt.Errorf("%w: %q", ErrNoCodec, p.Suffix()))
+	}
+	return codec, nil
+}
+
+// ReadStruct decodes the content of the file into the value pointed to by v,
+// using the codec registered for the file's suffix. An ErrNoCodec is returned
+// if there is none.
+func ReadStruct(p Path, v any) error {
+	codec, err := p.codecFor("decode")
+	if err != nil {
+		return err
+	}
+	return p.decode(codec, v)
+}
+
+// WriteStruct encodes the value to the file, using the codec registered for
+// the file's suffix. An ErrNoCodec is returned if there is none.
+func WriteStruct(p Path, v any, opts *EncodeOpts) error {
+	codec, err := p.codecFor("encode")
+	if err != nil {
+		return err
+	}
+	return p.encode(codec, v, opts)
+}
+
+// -----------------------------------------------------------------------------
+//
+// Typed helpers
+//
+// -----------------------------------------------------------------------------
+
+// ReadJSON decodes the JSON content of the file into a value of type T.
+func ReadJSON[T any](p Path) (T, error) {
+	return readAs[T](p, JSONCodec)
+}
+
+// WriteJSON encodes the value as JSON to the file.
+func WriteJSON(p Path, v any, opts *EncodeOpts) error {
+	return p.encode(JSONCodec, v, opts)
+}
+
+// ReadXML decodes the XML content of the file into a value of type T.
+func ReadXML[T any](p Path) (T, error) {
+	return readAs[T](p, XMLCodec)
+}
+
+// WriteXML encodes the value as XML to the file.
+func WriteXML(p Path, v any, opts *EncodeOpts) error {
+	return p.encode(XMLCodec, v, opts)
+}
+
+// ReadGob decodes the gob content of the file into a value of type T.
+func ReadGob[T any](p Path) (T, error) {
+	return readAs[T](p, GobCodec)
+}
+
+// WriteGob encodes the value as gob to the file.
+func WriteGob(p Path, v any, opts *EncodeOpts) error {
+	return p.encode(GobCodec, v, opts)
+}
+
+// readAs decodes the content of the file into a value of type T.
+func readAs[T any](p Path, codec Codec) (T, error) {
+	var v T
+	err := p.decode(codec, &v)
+	return v, err
+}
+
+// decode decodes the content of the file into the value pointed to by v.
+func (p Path) decode(codec Codec, v any) error {
+	file, err := p.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return p.pathError("decode", codec.Decode(bufio.NewReader(file), v))
+}
+
+// encode encodes the value to the file.
+func (p Path) encode(codec Codec, v any, opts *EncodeOpts) error {
+	if opts == nil {
+		return p.pathError("encode", fmt.Errorf("opts can't be nil"))
+	}
+	mode := opts.Mode
+	if mode == 0 {
+		mode = p.DefaultFileMode
+	}
+	write := func(w io.Writer) error {
+		buffered := bufio.NewWriter(w)
+		if err := codec.Encode(buffered, v, opts); err != nil {
+			return p.pathError("encode", err)
+		}
+		return p.pathError("write", buffered.Flush())
+	}
+
+	if opts.Atomic {
+		return p.writeAtomic(mode, write)
+	}
+	file, err := p.OpenFile(os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return p.pathError("close", file.Close())
+}
+
+// writeAtomic writes to a temporary file in the same directory, which then
+// replaces the file. The permission bits of an existing file are retained,
+// otherwise mode is used.
+func (p Path) writeAtomic(mode os.FileMode, write func(w io.Writer) error) (err error) {
+	if info, err := p.Stat(); err == nil {
+		mode = info.Mode().Perm()
+	}
+	// unlike TempFile, an empty parent stands for the working directory here
+	dir := p.Parent()
+	handle, err := afero.TempFile(p.Fs(), dir.String(), "."+p.Name()+".*.tmp")
+	if err != nil {
+		return dir.pathError("createtemp", err)
+	}
+	file, tmp := &File{File: handle}, copyPathWithPaths(p, handle.Name())
+	defer func() {
+		if err != nil {
+			tmp.Remove()
+		}
+	}()
+
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return tmp.pathError("sync", err)
+	}
+	if err := file.Close(); err != nil {
+		return tmp.pathError("close", err)
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		return err
+	}
+	_, err = tmp.RenamePath(p)
+	return err
+}
@@ -0,0 +1,150 @@
+package pathlib
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/aisbergg/go-pathlib/internal/testutils"
+	"github.com/spf13/afero"
+)
+
+type codecTestConfig struct {
+	Name  string   `json:"name" xml:"name"`
+	Ports []int    `json:"ports" xml:"port"`
+	Tags  []string `json:"tags" xml:"tag"`
+}
+
+var testConfig = codecTestConfig{
+	Name:  "service",
+	Ports: []int{80, 443},
+	Tags:  []string{"a", "b"},
+}
+
+func TestJSON(t *testing.T) {
+	for _, afs := range []afero.Fs{afero.NewOsFs(), afero.NewMemMapFs()} {
+		assert := testutils.NewAssert(t)
+		require := testutils.NewRequire(t)
+		file := tempDirForTest(t, afs).Join("config.json")
+
+		opts := DefaultEncodeOpts()
+		opts.Indent = "  "
+		require.NoError(WriteJSON(file, testConfig, opts))
+		content, err := file.ReadFile()
+		require.NoError(err)
+		assert.True(strings.HasPrefix(string(content), "{\n  \"name\": \"service\","))
+
+		config, err := ReadJSON[codecTestConfig](file)
+		require.NoError(err)
+		assert.Equal(testConfig, config)
+
+		opts = DefaultEncodeOpts()
+		opts.Atomic = false
+		require.NoError(WriteJSON(file, map[string]int{"a": 1}, opts))
+		values, err := ReadJSON[map[string]int](file)
+		require.NoError(err)
+		assert.Equal(map[string]int{"a": 1}, values)
+
+		require.NoError(file.WriteFile([]byte("{")))
+		_, err = ReadJSON[codecTestConfig](file)
+		var pathErr *PathError
+		require.True(errors.As(err, &pathErr))
+		assert.Equal("decode", pathErr.Op)
+
+		_, err = ReadJSON[codecTestConfig](file.Parent().Join("i_dont_exist.json"))
+		assert.True(errors.Is(err, os.ErrNotExist))
+
+		assert.Error(WriteJSON(file, testConfig, nil))
+	}
+}
+
+func TestXMLAndGob(t *testing.T) {
+	for _, afs := range []afero.Fs{afero.NewOsFs(), afero.NewMemMapFs()} {
+		assert := testutils.NewAssert(t)
+		require := testutils.NewRequire(t)
+		dir := tempDirForTest(t, afs)
+
+		xmlFile := dir.Join("config.xml")
+		require.NoError(WriteXML(xmlFile, testConfig, DefaultEncodeOpts()))
+		config, err := ReadXML[codecTestConfig](xmlFile)
+		require.NoError(err)
+		assert.Equal(testConfig, config)
+
+		gobFile := dir.Join("config.gob")
+		require.NoError(WriteGob(gobFile, testConfig, DefaultEncodeOpts()))
+		config, err = ReadGob[codecTestConfig](gobFile)
+		require.NoError(err)
+		assert.Equal(testConfig, config)
+	}
+}
+
+func TestAtomicWriteRetainsMode(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	file := tmpdir.Join("config.json")
+	require.NoError(file.WriteFile([]byte("{}"), 0o600))
+
+	require.NoError(WriteJSON(file, testConfig, DefaultEncodeOpts()))
+	info, err := file.Stat()
+	require.NoError(err)
+	assert.Equal(os.FileMode(0o600), info.Mode().Perm())
+
+	// no temporary files are left behind
+	children, err := tmpdir.ReadDir()
+	require.NoError(err)
+	assert.Equal(1, len(children))
+
+	// a failing encoder leaves the file untouched
+	assert.Error(WriteJSON(file, func() {}, DefaultEncodeOpts()))
+	config, err := ReadJSON[codecTestConfig](file)
+	require.NoError(err)
+	assert.Equal(testConfig, config)
+	children, err = tmpdir.ReadDir()
+	require.NoError(err)
+	assert.Equal(1, len(children))
+}
+
+type upperCodec struct{}
+
+func (upperCodec) Encode(w io.Writer, v any, opts *EncodeOpts) error {
+	_, err := io.WriteString(w, strings.ToUpper(*v.(*string)))
+	return err
+}
+
+func (upperCodec) Decode(r io.Reader, v any) error {
+	data, err := io.ReadAll(r)
+	*v.(*string) = strings.ToLower(string(data))
+	return err
+}
+
+func TestCodecRegistry(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	dir := tempDirForTest(t, afero.NewMemMapFs())
+
+	var config codecTestConfig
+	require.NoError(WriteStruct(dir.Join("config.JSON"), testConfig, DefaultEncodeOpts()))
+	require.NoError(ReadStruct(dir.Join("config.JSON"), &config))
+	assert.Equal(testConfig, config)
+
+	unknown := dir.Join("config.upper")
+	assert.True(errors.Is(WriteStruct(unknown, testConfig, DefaultEncodeOpts()), ErrNoCodec))
+	assert.True(errors.Is(ReadStruct(unknown, &config), ErrNoCodec))
+
+	RegisterCodec(".upper", upperCodec{})
+	defer func() {
+		codecsMu.Lock()
+		delete(codecs, ".upper")
+		codecsMu.Unlock()
+	}()
+	value := "hello"
+	require.NoError(WriteStruct(unknown, &value, DefaultEncodeOpts()))
+	content, err := unknown.ReadFile()
+	require.NoError(err)
+	assert.Equal("HELLO", string(content))
+	var decoded string
+	require.NoError(ReadStruct(unknown, &decoded))
+	assert.Equal("hello", decoded)
+}
@@ -0,0 +1,111 @@
+package pathlib
+
+import (
+	"context"
+	"io"
+	"os"
+)
+
+// contextChunkSize is the number of bytes transferred between two checks of
+// the context.
+const contextChunkSize = 32 * 1024
+
+// contextErr returns the error of the done context wrapped with the operation
+// and path involved, or nil if the context is not done.
+func contextErr(ctx context.Context, op string, p Path) error {
+	if err := ctx.Err(); err != nil {
+		return p.pathError(op, err)
+	}
+	return nil
+}
+
+// copyContext copies from src, the content of p, to dst, the content of
+// target, in chunks and checks the context before each of them. p is the path
+// reported if the context is done or reading fails, target the one reported
+// if writing fails.
+func copyContext(ctx context.Context, dst io.Writer, target Path, src io.Reader, p Path, op string) error {
+	buf := make([]byte, contextChunkSize)
+	for {
+		if err := contextErr(ctx, op, p); err != nil {
+			return err
+		}
+		n, err := src.Read(buf)
+		if n > 0 {
+			if _, werr := dst.Write(buf[:n]); werr != nil {
+				return target.pathError("write", werr)
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return p.pathError("read", err)
+		}
+	}
+}
+
+// ReadFileContext is like ReadFile but stops once the context is done. The
+// context is checked between chunks of the file; the returned error wraps
+// ctx.Err() with the path.
+func (p Path) ReadFileContext(ctx context.Context) ([]byte, error) {
+	if err := contextErr(ctx, "read", p); err != nil {
+		return nil, err
+	}
+	file, err := p.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var size int64
+	if info, err := file.Stat(); err == nil {
+		size = info.Size()
+	}
+	buf := &sliceWriter{data: make([]byte, 0, size)}
+	if err := copyContext(ctx, buf, p, file, p, "read"); err != nil {
+		return nil, err
+	}
+	return buf.data, nil
+}
+
+// WriteFileContext is like WriteFile but stops once the context is done. The
+// context is checked between chunks of the data; the returned error wraps
+// ctx.Err() with the path. The file may be partially written then.
+func (p Path) WriteFileContext(ctx context.Context, data []byte, perm ...os.FileMode) (err error) {
+	if err := contextErr(ctx, "write", p); err != nil {
+		return err
+	}
+	file, err := p.OpenFile(os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := file.Close(); err == nil {
+			err = p.pathError("close", cerr)
+		}
+	}()
+	for len(data) > 0 {
+		if err := contextErr(ctx, "write", p); err != nil {
+			return err
+		}
+		n := contextChunkSize
+		if n > len(data) {
+			n = len(data)
+		}
+		if _, err := file.Write(data[:n]); err != nil {
+			return p.pathError("write", err)
+		}
+		data = data[n:]
+	}
+	return nil
+}
+
+// sliceWriter is an io.Writer appending to a byte slice.
+type sliceWriter struct {
+	data []byte
+}
+
+func (w *sliceWriter) Write(b []byte) (int, error) {
+	w.data = append(w.data, b...)
+	return len(b), nil
+}
@@ -0,0 +1,117 @@
+package pathlib
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// CopyTo copies the file or directory tree to the target, which may reside on
+// a different afero filesystem. Existing files are overwritten, existing
+// directories are merged. The permission bits are preserved, symlinks are
+// copied as symlinks.
+//
+// Copying symlinks will fail if the target's afero filesystem does not
+// implement afero.Linker. Other special files can't be copied.
+func (p Path) CopyTo(target Path) error {
+	return p.CopyToContext(context.Background(), target)
+}
+
+// CopyToContext is like CopyTo but stops once the context is done. The context
+// is checked before each directory entry and between chunks of file data; the
+// returned error wraps ctx.Err() with the path being copied.
+func (p Path) CopyToContext(ctx context.Context, target Path) error {
+	if sameFs(p.Fs(), target.Fs()) {
+		// compare the resolved paths, so that neither relative paths nor
+		// symlinks hide that the target lies within the source
+		resolvedSrc, err := p.Resolve(false)
+		if err != nil {
+			return err
+		}
+		resolvedDst, err := target.Resolve(false)
+		if err != nil {
+			return err
+		}
+		src := resolvedSrc.String()
+		dst := resolvedDst.String()
+		sep := p.flavor.Separator()
+		if dst == src || strings.HasPrefix(dst, strings.TrimSuffix(src, sep)+sep) {
+			return p.pathError("copy", fmt.Errorf("cannot copy into itself: %s", target))
+		}
+	}
+	return p.copyTo(ctx, target)
+}
+
+func (p Path) copyTo(ctx context.Context, target Path) error {
+	if err := contextErr(ctx, "copy", p); err != nil {
+		return err
+	}
+	info, err := p.lstatIfPossible()
+	if err != nil {
+		return err
+	}
+
+	switch TypeOf(info.Mode()) {
+	case FileTypeRegular:
+		return p.copyFileTo(ctx, target, info.Mode().Perm())
+	case FileTypeSymlink:
+		link, err := p.Readlink()
+		if err != nil {
+			return err
+		}
+		// a dangling symlink at the target has to be replaced as well
+		if _, err := target.lstatIfPossible(); err == nil {
+			if err := target.Remove(); err != nil {
+				return err
+			}
+		}
+		if _, ok := target.Fs().(afero.Linker); !ok {
+			return target.pathError("symlink", target.doesNotImplementErr("afero.Linker"))
+		}
+		return target.SymlinkStr(link.String())
+	case FileTypeDir:
+		// the owner needs to be able to write the children; the actual mode is
+		// applied once they are copied
+		if err := target.MkdirAll(info.Mode().Perm() | 0o700); err != nil {
+			return err
+		}
+		children, err := p.ReadDir()
+		if err != nil {
+			return err
+		}
+		for _, child := range children {
+			if err := child.copyTo(ctx, target.Join(child.Name())); err != nil {
+				return err
+			}
+		}
+		return target.Chmod(info.Mode().Perm())
+	default:
+		return p.pathError("copy", fmt.Errorf("unsupported file type: %s", TypeOf(info.Mode())))
+	}
+}
+
+func (p Path) copyFileTo(ctx context.Context, target Path, perm os.FileMode) (err error) {
+	src, err := p.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := target.OpenFile(os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := dst.Close(); err == nil {
+			err = target.pathError("close", cerr)
+		}
+	}()
+	if err := copyContext(ctx, dst, target, src, p, "copy"); err != nil {
+		return err
+	}
+	// the mode of new files is subject to the umask
+	return target.Chmod(perm)
+}
@@ -0,0 +1,212 @@
+package pathlib
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/aisbergg/go-pathlib/internal/testutils"
+	"github.com/spf13/afero"
+)
+
+func TestCopyTo(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	src := tmpdir.Join("src")
+	require.NoError(src.Join("subdir").MkdirAll())
+	require.NoError(src.Join("file.txt").WriteFile([]byte("hello"), 0o600))
+	require.NoError(src.Join("subdir", "nested.txt").WriteFile([]byte("world")))
+	require.NoError(src.Join("link").SymlinkStr("file.txt"))
+
+	dst := tmpdir.Join("dst")
+	require.NoError(src.CopyTo(dst))
+	content, err := dst.Join("file.txt").ReadFile()
+	require.NoError(err)
+	assert.Equal("hello", string(content))
+	info, err := dst.Join("file.txt").Stat()
+	require.NoError(err)
+	assert.Equal(os.FileMode(0o600), info.Mode().Perm())
+	content, err = dst.Join("subdir", "nested.txt").ReadFile()
+	require.NoError(err)
+	assert.Equal("world", string(content))
+	link, err := dst.Join("link").Readlink()
+	require.NoError(err)
+	assert.Equal("file.txt", link.String())
+
+	// copying again overwrites
+	require.NoError(src.Join("file.txt").WriteFile([]byte("changed")))
+	require.NoError(src.CopyTo(dst))
+	content, err = dst.Join("file.txt").ReadFile()
+	require.NoError(err)
+	assert.Equal("changed", string(content))
+
+	// across filesystems
+	mem := NewPathWithFS(afero.NewMemMapFs(), "/copy")
+	require.NoError(src.Join("subdir").CopyTo(mem))
+	content, err = mem.Join("nested.txt").ReadFile()
+	require.NoError(err)
+	assert.Equal("world", string(content))
+
+	assert.Error(src.CopyTo(src.Join("subdir", "again")))
+	assert.True(errors.Is(tmpdir.Join("i_dont_exist").CopyTo(dst), fs.ErrNotExist))
+}
+
+func TestCopyToItself(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	src := tmpdir.Join("src")
+	require.NoError(src.Mkdir())
+	require.NoError(src.Join("file.txt").WriteFile([]byte("hello")))
+
+	// relative source, absolute target
+	cwd, err := os.Getwd()
+	require.NoError(err)
+	defer os.Chdir(cwd)
+	require.NoError(os.Chdir(tmpdir.String()))
+	err = NewPath("src").CopyTo(src.Join("again"))
+	var pathErr *PathError
+	require.True(errors.As(err, &pathErr))
+	assert.Equal("copy", pathErr.Op)
+
+	// target reached through a symlink
+	require.NoError(tmpdir.Join("alias").Symlink(src))
+	assert.Error(src.CopyTo(tmpdir.Join("alias", "again")))
+
+	exists, err := src.Join("again").Exists()
+	require.NoError(err)
+	assert.False(exists)
+}
+
+func TestCopyToReadOnlyTree(t *testing.T) {
+	if runtime.GOOS == "windows" || os.Getuid() == 0 {
+		t.Skip("permission bits are not enforced")
+	}
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	src := tmpdir.Join("src")
+	require.NoError(src.Join("sub").MkdirAll())
+	require.NoError(src.Join("sub", "file.txt").WriteFile([]byte("hello")))
+	require.NoError(src.Join("sub").Chmod(0o555))
+	require.NoError(src.Chmod(0o555))
+	dst := tmpdir.Join("dst")
+	defer func() {
+		for _, dir := range []Path{src, src.Join("sub"), dst, dst.Join("sub")} {
+			dir.Chmod(0o755)
+		}
+	}()
+
+	require.NoError(src.CopyTo(dst))
+	content, err := dst.Join("sub", "file.txt").ReadFile()
+	require.NoError(err)
+	assert.Equal("hello", string(content))
+	info, err := dst.Join("sub").Stat()
+	require.NoError(err)
+	assert.Equal(os.FileMode(0o555), info.Mode().Perm())
+}
+
+func TestCopyToDanglingSymlink(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	src := tmpdir.Join("link")
+	require.NoError(src.SymlinkStr("file.txt"))
+	dst := tmpdir.Join("dst")
+	require.NoError(dst.SymlinkStr("i_dont_exist"))
+
+	require.NoError(src.CopyTo(dst))
+	link, err := dst.Readlink()
+	require.NoError(err)
+	assert.Equal("file.txt", link.String())
+}
+
+// uncomparableFs is an afero filesystem that panics when compared with ==.
+type uncomparableFs struct {
+	afero.Fs
+	names []string
+}
+
+func TestCopyToUncomparableFs(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	afs := uncomparableFs{Fs: afero.NewMemMapFs()}
+	src := NewPathWithFS(afs, "/src")
+	require.NoError(src.MkdirAll())
+	require.NoError(src.Join("file.txt").WriteFile([]byte("hello")))
+
+	dst := NewPathWithFS(uncomparableFs{Fs: afs.Fs}, "/dst")
+	require.NoError(src.CopyTo(dst))
+	content, err := dst.Join("file.txt").ReadFile()
+	require.NoError(err)
+	assert.Equal("hello", string(content))
+}
+
+func TestContextCancelled(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	root := NewPathWithFS(afero.NewMemMapFs(), "/root")
+	require.NoError(root.MkdirAll())
+	require.NoError(TwoFilesAtRootTwoInSubdir(root))
+	file := root.Join("file0.txt")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var pathErr *PathError
+
+	walk, err := NewWalk(root)
+	require.NoError(err)
+	err = walk.WalkContext(ctx, func(path Path, info os.FileInfo, err error) error {
+		t.Error("walk function called after cancellation")
+		return nil
+	})
+	assert.True(errors.Is(err, context.Canceled))
+	assert.True(errors.As(err, &pathErr))
+
+	_, err = file.ReadFileContext(ctx)
+	assert.True(errors.Is(err, context.Canceled))
+	require.True(errors.As(err, &pathErr))
+	assert.Equal(file.String(), pathErr.Path)
+
+	err = file.WriteFileContext(ctx, []byte("hello"))
+	assert.True(errors.Is(err, context.Canceled))
+
+	err = root.CopyToContext(ctx, NewPathWithFS(afero.NewMemMapFs(), "/copy"))
+	assert.True(errors.Is(err, context.Canceled))
+}
+
+func TestContextWalkStopsEarly(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	root := NewPathWithFS(afero.NewMemMapFs(), "/root")
+	require.NoError(root.MkdirAll())
+	require.NoError(NFiles(root, 10))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	walk, err := NewWalk(root)
+	require.NoError(err)
+	visited := 0
+	err = walk.WalkContext(ctx, func(path Path, info os.FileInfo, err error) error {
+		visited++
+		cancel()
+		return nil
+	})
+	assert.True(errors.Is(err, context.Canceled))
+	assert.Equal(1, visited)
+}
+
+func TestReadWriteFileContext(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	file := NewPathWithFS(afero.NewMemMapFs(), "/file.txt")
+	data := make([]byte, 3*contextChunkSize+7)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	require.NoError(file.WriteFileContext(context.Background(), data))
+	content, err := file.ReadFileContext(context.Background())
+	require.NoError(err)
+	assert.Equal(data, content)
+}
@@ -0,0 +1,199 @@
+package pathlib
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"sort"
+)
+
+// SortKey is the property of the directory entries ReadDirSorted sorts by.
+type SortKey int
+
+const (
+	// SortByName sorts by name, comparing the bytes of the names.
+	SortByName SortKey = iota
+	// SortByNaturalName sorts by name, comparing sequences of digits by their
+	// numeric value, so that "file2" comes before "file10".
+	SortByNaturalName
+	// SortByMtime sorts by modification time.
+	SortByMtime
+	// SortBySize sorts by size in bytes.
+	SortBySize
+	// SortByType sorts by file type, in the order of the FileType constants.
+	SortByType
+)
+
+// SortOrder is the direction ReadDirSorted sorts in.
+type SortOrder int
+
+const (
+	// Ascending sorts the smallest value first.
+	Ascending SortOrder = iota
+	// Descending sorts the largest value first.
+	Descending
+)
+
+// DirFilter decides whether a directory entry is included in the result of
+// ReadDirSorted. info is the result of Stat for the entry.
+type DirFilter func(path Path, info os.FileInfo) bool
+
+// sortedEntry is a directory entry with its cached file information.
+type sortedEntry struct {
+	path Path
+	info os.FileInfo
+}
+
+// ReadDirSorted returns the paths of the directory's entries sorted by the
+// given key and order. Entries with equal keys are sorted by name in
+// ascending order. If filter is not nil, only the entries it accepts are
+// returned.
+//
+// Every entry is stat'ed at most once, or not at all where the directory
+// listing already carries the file information; symlinks are followed.
+// Dangling symlinks are sorted by the information of the link itself. Entries
+// that are removed while the directory is read are omitted.
+func (p Path) ReadDirSorted(by SortKey, order SortOrder, filter DirFilter) ([]Path, error) {
+	dirEntries, err := p.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]sortedEntry, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		child := dirEntry.Path()
+		var info os.FileInfo
+		if IsSymlink(dirEntry.Type()) {
+			info, err = child.Stat()
+			if errors.Is(err, fs.ErrNotExist) {
+				info, err = dirEntry.Info()
+			}
+		} else {
+			info, err = dirEntry.Info()
+		}
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		if filter != nil && !filter(child, info) {
+			continue
+		}
+		entries = append(entries, sortedEntry{path: child, info: info})
+	}
+
+	compare := sortKeyCompare(by)
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if c := compare(a.info, b.info); c != 0 {
+			if order == Descending {
+				return c > 0
+			}
+			return c < 0
+		}
+		return a.info.Name() < b.info.Name()
+	})
+
+	paths := make([]Path, len(entries))
+	for i, entry := range entries {
+		paths[i] = entry.path
+	}
+	return paths, nil
+}
+
+// sortKeyCompare returns a function that compares two entries by the given
+// key, returning a negative number, zero or a positive number if a is less
+// than, equal to or greater than b.
+func sortKeyCompare(by SortKey) func(a, b os.FileInfo) int {
+	switch by {
+	case SortByNaturalName:
+		return func(a, b os.FileInfo) int {
+			return naturalCompare(a.Name(), b.Name())
+		}
+	case SortByMtime:
+		return func(a, b os.FileInfo) int {
+			switch {
+			case a.ModTime().Before(b.ModTime()):
+				return -1
+			case a.ModTime().After(b.ModTime()):
+				return 1
+			}
+			return 0
+		}
+	case SortBySize:
+		return func(a, b os.FileInfo) int {
+			switch {
+			case a.Size() < b.Size():
+				return -1
+			case a.Size() > b.Size():
+				return 1
+			}
+			return 0
+		}
+	case SortByType:
+		return func(a, b os.FileInfo) int {
+			return int(TypeOf(a.Mode())) - int(TypeOf(b.Mode()))
+		}
+	default:
+		return func(a, b os.FileInfo) int {
+			switch {
+			case a.Name() < b.Name():
+				return -1
+			case a.Name() > b.Name():
+				return 1
+			}
+			return 0
+		}
+	}
+}
+
+// naturalCompare compares two strings like a human would, treating sequences
+// of digits as numbers. Numbers with leading zeros compare equal to the same
+// number without them; the shorter representation is sorted first then.
+func naturalCompare(a, b string) int {
+	for len(a) > 0 && len(b) > 0 {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			numA, restA := splitDigits(a)
+			numB, restB := splitDigits(b)
+			trimmedA, trimmedB := trimLeadingZeros(numA), trimLeadingZeros(numB)
+			switch {
+			case len(trimmedA) != len(trimmedB):
+				return len(trimmedA) - len(trimmedB)
+			case trimmedA < trimmedB:
+				return -1
+			case trimmedA > trimmedB:
+				return 1
+			case len(numA) != len(numB):
+				return len(numA) - len(numB)
+			}
+			a, b = restA, restB
+			continue
+		}
+		if a[0] != b[0] {
+			return int(a[0]) - int(b[0])
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) - len(b)
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// splitDigits splits the leading sequence of digits from s.
+func splitDigits(s string) (digits, rest string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func trimLeadingZeros(s string) string {
+	for len(s) > 1 && s[0] == '0' {
+		s = s[1:]
+	}
+	return s
+}
@@ -0,0 +1,154 @@
+package pathlib
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aisbergg/go-pathlib/internal/testutils"
+	"github.com/spf13/afero"
+)
+
+func names(paths []Path) []string {
+	names := make([]string, len(paths))
+	for i, path := range paths {
+		names[i] = path.Name()
+	}
+	return names
+}
+
+// setupSortTest creates files with increasing mtimes and decreasing sizes in
+// the order given.
+func setupSortTest(t *testing.T, fs afero.Fs, files ...string) Path {
+	require := testutils.NewRequire(t)
+	dir := tempDirForTest(t, fs)
+	now := time.Now()
+	for i, name := range files {
+		file := dir.Join(name)
+		require.NoError(file.WriteFile([]byte(strings.Repeat("x", len(files)-i))))
+		require.NoError(file.Chtimes(now, now))
+		now = now.Add(time.Hour)
+	}
+	return dir
+}
+
+func TestReadDirSorted(t *testing.T) {
+	for _, fs := range []afero.Fs{afero.NewOsFs(), afero.NewMemMapFs()} {
+		assert := testutils.NewAssert(t)
+		require := testutils.NewRequire(t)
+		dir := setupSortTest(t, fs, "b10.txt", "a.txt", "b2.txt")
+		require.NoError(dir.Join("c").Mkdir())
+		require.NoError(dir.Join("c").Chtimes(time.Unix(0, 0), time.Unix(0, 0)))
+
+		entries, err := dir.ReadDirSorted(SortByName, Ascending, nil)
+		require.NoError(err)
+		assert.Equal([]string{"a.txt", "b10.txt", "b2.txt", "c"}, names(entries))
+
+		entries, err = dir.ReadDirSorted(SortByName, Descending, nil)
+		require.NoError(err)
+		assert.Equal([]string{"c", "b2.txt", "b10.txt", "a.txt"}, names(entries))
+
+		entries, err = dir.ReadDirSorted(SortByNaturalName, Ascending, nil)
+		require.NoError(err)
+		assert.Equal([]string{"a.txt", "b2.txt", "b10.txt", "c"}, names(entries))
+
+		entries, err = dir.ReadDirSorted(SortByMtime, Ascending, nil)
+		require.NoError(err)
+		assert.Equal([]string{"c", "b10.txt", "a.txt", "b2.txt"}, names(entries))
+
+		entries, err = dir.ReadDirSorted(SortByType, Descending, nil)
+		require.NoError(err)
+		assert.Equal([]string{"c", "a.txt", "b10.txt", "b2.txt"}, names(entries))
+
+		filesOnly := func(path Path, info os.FileInfo) bool {
+			return info.Mode().IsRegular()
+		}
+		entries, err = dir.ReadDirSorted(SortBySize, Descending, filesOnly)
+		require.NoError(err)
+		assert.Equal([]string{"b10.txt", "a.txt", "b2.txt"}, names(entries))
+
+		_, err = dir.Join("i_dont_exist").ReadDirSorted(SortByName, Ascending, nil)
+		assert.Error(err)
+	}
+}
+
+func TestReadDirSortedSymlink(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	require.NoError(TwoFilesAtRootTwoInSubdir(tmpdir))
+	require.NoError(tmpdir.Join("link").Symlink(tmpdir.Join("subdir")))
+	counting := &infoCountingFs{Fs: afero.NewOsFs()}
+	dir := NewPathWithFS(counting, tmpdir.String())
+
+	entries, err := dir.ReadDirSorted(SortByType, Ascending, nil)
+	require.NoError(err)
+	assert.Equal([]string{"file0.txt", "file1.txt", "link", "subdir"}, names(entries))
+	// the symlink is stat'ed right away instead of being lstat'ed first
+	assert.Equal(int64(3), counting.infos)
+}
+
+func TestReadDirSortedDanglingSymlink(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	require.NoError(TwoFilesAtRootTwoInSubdir(tmpdir))
+	require.NoError(tmpdir.Join("dangling").SymlinkStr("i_dont_exist"))
+
+	entries, err := tmpdir.ReadDirSorted(SortByType, Ascending, nil)
+	require.NoError(err)
+	assert.Equal([]string{"file0.txt", "file1.txt", "subdir", "dangling"}, names(entries))
+}
+
+func TestGetLatestOldestLargest(t *testing.T) {
+	for _, fs := range []afero.Fs{afero.NewOsFs(), afero.NewMemMapFs()} {
+		assert := testutils.NewAssert(t)
+		require := testutils.NewRequire(t)
+		// the newest file is listed last by MemMapFs
+		dir := setupSortTest(t, fs, "file0.txt", "file1.txt", "file2.txt")
+
+		latest, err := dir.GetLatest()
+		require.NoError(err)
+		assert.Equal("file2.txt", latest.Name())
+
+		oldest, err := dir.GetOldest()
+		require.NoError(err)
+		assert.Equal("file0.txt", oldest.Name())
+
+		largest, err := dir.GetLargest()
+		require.NoError(err)
+		assert.Equal("file0.txt", largest.Name())
+
+		newest, err := dir.Newest(2)
+		require.NoError(err)
+		assert.Equal([]string{"file2.txt", "file1.txt"}, names(newest))
+		newest, err = dir.Newest(0)
+		require.NoError(err)
+		assert.Equal([]string{"file2.txt", "file1.txt", "file0.txt"}, names(newest))
+
+		empty := dir.Join("empty")
+		require.NoError(empty.Mkdir())
+		_, err = empty.GetOldest()
+		assert.EqualError(ErrDirectoryEmpty, err)
+		_, err = empty.GetLargest()
+		assert.EqualError(ErrDirectoryEmpty, err)
+	}
+}
+
+func TestNaturalCompare(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	for _, c := range []struct {
+		a, b string
+		less bool
+	}{
+		{"file2", "file10", true},
+		{"file10", "file2", false},
+		{"a", "b", true},
+		{"a1b2", "a1b10", true},
+		{"file02", "file2", false},
+		{"file2", "file02", true},
+		{"file", "file1", true},
+		{"10", "9", false},
+	} {
+		assert.Equal(c.less, naturalCompare(c.a, c.b) < 0, "%s < %s", c.a, c.b)
+	}
+}
@@ -0,0 +1,110 @@
+package pathlib
+
+import (
+	"io/fs"
+	"os"
+)
+
+// DirEntry is an entry of a directory as returned by Entries. The type bits of
+// the entry are known up front, the complete file information is retrieved on
+// the first call to Info and cached afterwards. DirEntry implements
+// fs.DirEntry. It is not safe for concurrent use.
+type DirEntry struct {
+	path Path
+	typ  os.FileMode
+	info os.FileInfo
+	// load retrieves the file information if it is not known yet
+	load func() (os.FileInfo, error)
+}
+
+var _ fs.DirEntry = (*DirEntry)(nil)
+
+// Path returns the path of the entry.
+func (e *DirEntry) Path() Path {
+	return e.path
+}
+
+// Name returns the name of the entry.
+func (e *DirEntry) Name() string {
+	return e.path.Name()
+}
+
+// IsDir returns whether the entry is a directory. Symlinks are not followed.
+func (e *DirEntry) IsDir() bool {
+	return e.typ.IsDir()
+}
+
+// Type returns the type bits of the entry. Symlinks are not followed.
+func (e *DirEntry) Type() os.FileMode {
+	return e.typ
+}
+
+// Info returns the file information of the entry, like Lstat would. It is
+// retrieved at most once, unless retrieving it fails. If the entry has been
+// removed since the directory was read, an error matching fs.ErrNotExist is
+// returned.
+func (e *DirEntry) Info() (os.FileInfo, error) {
+	if e.info == nil {
+		info, err := e.load()
+		if err != nil {
+			return nil, e.path.pathError("lstat", err)
+		}
+		e.info = info
+	}
+	return e.info, nil
+}
+
+// dirEntryReader is implemented by the files of afero filesystems that can
+// list a directory without retrieving the complete file information of every
+// entry, like *os.File.
+type dirEntryReader interface {
+	ReadDir(n int) ([]fs.DirEntry, error)
+}
+
+// Entries returns the entries of the directory. Unlike ReadDir, the entries
+// carry their type, so callers that only need the type of an entry don't have
+// to call Stat or Lstat on it.
+//
+// Filesystems whose files implement ReadDir like *os.File are asked for the
+// types only, and the complete file information is retrieved on demand with
+// an Lstat call. For all other filesystems the file information is retrieved
+// together with the names, so Info doesn't cost another call.
+func (p Path) Entries() ([]*DirEntry, error) {
+	handle, err := p.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer handle.Close()
+
+	if reader, ok := handle.File.(dirEntryReader); ok {
+		dirEntries, err := reader.ReadDir(-1)
+		if err != nil {
+			return nil, p.pathError("readdir", err)
+		}
+		entries := make([]*DirEntry, 0, len(dirEntries))
+		for _, dirEntry := range dirEntries {
+			entries = append(entries, &DirEntry{
+				path: p.Join(dirEntry.Name()),
+				typ:  dirEntry.Type(),
+				load: func() (os.FileInfo, error) {
+					return dirEntry.Info()
+				},
+			})
+		}
+		return entries, nil
+	}
+
+	infos, err := handle.Readdir(-1)
+	if err != nil {
+		return nil, p.pathError("readdir", err)
+	}
+	entries := make([]*DirEntry, 0, len(infos))
+	for _, info := range infos {
+		entries = append(entries, &DirEntry{
+			path: p.Join(info.Name()),
+			typ:  info.Mode().Type(),
+			info: info,
+		})
+	}
+	return entries, nil
+}
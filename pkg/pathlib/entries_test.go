@@ -0,0 +1,115 @@
+package pathlib
+
+import (
+	"errors"
+	"io/fs"
+	"sort"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aisbergg/go-pathlib/internal/testutils"
+	"github.com/spf13/afero"
+)
+
+func TestEntries(t *testing.T) {
+	for _, afs := range []afero.Fs{afero.NewOsFs(), afero.NewMemMapFs()} {
+		assert := testutils.NewAssert(t)
+		require := testutils.NewRequire(t)
+		root := tempDirForTest(t, afs)
+		require.NoError(TwoFilesAtRootTwoInSubdir(root))
+
+		entries, err := root.Entries()
+		require.NoError(err)
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].Name() < entries[j].Name()
+		})
+		require.Equal(3, len(entries))
+
+		assert.Equal("file0.txt", entries[0].Name())
+		assert.Equal(root.Join("file0.txt"), entries[0].Path())
+		assert.False(entries[0].IsDir())
+		assert.Equal(fs.FileMode(0), entries[0].Type())
+		info, err := entries[0].Info()
+		require.NoError(err)
+		assert.Equal(int64(len("file0 contents")), info.Size())
+
+		assert.Equal("subdir", entries[2].Name())
+		assert.True(entries[2].IsDir())
+		assert.Equal(fs.ModeDir, entries[2].Type())
+
+		_, err = root.Join("i_dont_exist").Entries()
+		assert.True(errors.Is(err, fs.ErrNotExist))
+	}
+}
+
+func TestEntriesSymlink(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	require.NoError(tmpdir.Join("dir").Mkdir())
+	require.NoError(tmpdir.Join("link").Symlink(tmpdir.Join("dir")))
+
+	entries, err := tmpdir.Entries()
+	require.NoError(err)
+	for _, entry := range entries {
+		if entry.Name() != "link" {
+			continue
+		}
+		assert.Equal(fs.ModeSymlink, entry.Type())
+		info, err := entry.Info()
+		require.NoError(err)
+		assert.True(IsSymlink(info.Mode()))
+	}
+}
+
+func TestEntriesRemoved(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	file := tmpdir.Join("file.txt")
+	require.NoError(file.WriteFile([]byte("hello")))
+
+	entries, err := tmpdir.Entries()
+	require.NoError(err)
+	require.Equal(1, len(entries))
+	require.NoError(file.Remove())
+
+	_, err = entries[0].Info()
+	assert.True(errors.Is(err, fs.ErrNotExist))
+}
+
+// infoCountingFs counts the calls to Info of the directory entries listed on
+// it. Its files implement ReadDir if the underlying ones do.
+type infoCountingFs struct {
+	afero.Fs
+	infos int64
+}
+
+func (c *infoCountingFs) Open(name string) (afero.File, error) {
+	file, err := c.Fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &infoCountingFile{File: file, fs: c}, nil
+}
+
+type infoCountingFile struct {
+	afero.File
+	fs *infoCountingFs
+}
+
+func (f *infoCountingFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	entries, err := f.File.(dirEntryReader).ReadDir(n)
+	for i, entry := range entries {
+		entries[i] = countedDirEntry{DirEntry: entry, fs: f.fs}
+	}
+	return entries, err
+}
+
+type countedDirEntry struct {
+	fs.DirEntry
+	fs *infoCountingFs
+}
+
+func (e countedDirEntry) Info() (fs.FileInfo, error) {
+	atomic.AddInt64(&e.fs.infos, 1)
+	return e.DirEntry.Info()
+}
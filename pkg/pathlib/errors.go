@@ -1,6 +1,14 @@
 package pathlib
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/afero/mem"
+)
 
 var (
 	// ErrDoesNotImplement indicates that the afero filesystem doesn't
@@ -16,4 +24,134 @@ var (
 	ErrStopWalk = fmt.Errorf("stop filesystem walk")
 	// ErrDirectoryEmpty indicates an empty directory
 	ErrDirectoryEmpty = fmt.Errorf("directory is empty")
+	// ErrUnknownHashAlgorithm indicates that no hash.Hash factory is
+	// registered for the requested hash algorithm
+	ErrUnknownHashAlgorithm = fmt.Errorf("unknown hash algorithm")
+	// ErrInvalidManifest indicates that a checksum manifest could not be parsed
+	ErrInvalidManifest = fmt.Errorf("invalid manifest")
+	// ErrSymlinkLoop indicates that too many symlinks were encountered while
+	// resolving a path, which usually means that symlinks form a loop
+	ErrSymlinkLoop = fmt.Errorf("too many levels of symbolic links")
+	// ErrStatUnavailable indicates that the requested stat information is not
+	// provided by the filesystem or platform
+	ErrStatUnavailable = fmt.Errorf("stat information is not available")
+	// ErrXattrNotFound indicates that the requested extended attribute does
+	// not exist
+	ErrXattrNotFound = fmt.Errorf("extended attribute not found")
+	// ErrInvalidMode indicates that a symbolic or octal file mode expression
+	// could not be parsed
+	ErrInvalidMode = fmt.Errorf("invalid file mode")
+	// ErrInvalidName indicates that a file name is empty or contains more than
+	// one path component
+	ErrInvalidName = fmt.Errorf("invalid name")
+	// ErrInvalidSuffix indicates that a file suffix doesn't start with a dot
+	// or contains a separator
+	ErrInvalidSuffix = fmt.Errorf("invalid suffix")
+	// ErrEmptyName indicates that the path has an empty name, which can't be
+	// changed
+	ErrEmptyName = fmt.Errorf("path has an empty name")
+	// ErrNotRelative indicates that a path is not relative to another path
+	ErrNotRelative = fmt.Errorf("path is not relative")
+	// ErrNoCodec indicates that no Codec is registered for the suffix of a
+	// file
+	ErrNoCodec = fmt.Errorf("no codec registered")
 )
+
+// PathError records an error and the operation, path and filesystem that
+// caused it. All methods of Path and PurePath return errors of this type,
+// except for sentinel errors like ErrStopWalk that are meant to be returned
+// as they are and errors returned by callbacks, which are passed through
+// unchanged.
+//
+// The underlying errors of the afero filesystems are kept, so errors.Is(err,
+// fs.ErrNotExist), fs.ErrExist and fs.ErrPermission work as they do for the
+// os package. The closed file errors of afero and its MemMapFs, which don't
+// wrap fs.ErrClosed, are normalized so that errors.Is(err, fs.ErrClosed)
+// works for them, too. Note that os.IsNotExist and friends don't look into
+// PathError, use errors.Is instead.
+type PathError struct {
+	// Op is the operation that failed, e.g. "open".
+	Op string
+	// Path is the path the operation failed on.
+	Path string
+	// Fs is the afero filesystem of the path, nil for the operations of
+	// PurePath.
+	Fs afero.Fs
+	// Err is the underlying error.
+	Err error
+}
+
+// Error returns the error message.
+func (e *PathError) Error() string {
+	return e.Op + " " + e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// pathError wraps the error in a PathError for the given operation, unless it
+// is nil or already wraps a PathError. An *os.PathError of the same path is
+// replaced, as it would only repeat the path.
+func (p Path) pathError(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var pathErr *PathError
+	if errors.As(err, &pathErr) {
+		if pathErr == err && pathErr.Fs == nil {
+			// raised by PurePath
+			withFs := *pathErr
+			withFs.Fs = p.Fs()
+			return &withFs
+		}
+		return err
+	}
+	if osErr, ok := err.(*os.PathError); ok && osErr.Path == p.String() {
+		err = osErr.Err
+	}
+	return &PathError{
+		Op:   op,
+		Path: p.String(),
+		Fs:   p.Fs(),
+		Err:  normalizeErr(err),
+	}
+}
+
+// pathError wraps the error in a PathError for the given operation.
+func (p PurePath) pathError(op string, err error) error {
+	return &PathError{
+		Op:   op,
+		Path: p.String(),
+		Err:  err,
+	}
+}
+
+// normalizedError adds the identity of a standard error to an error of an
+// afero filesystem that doesn't wrap it.
+type normalizedError struct {
+	err    error
+	target error
+}
+
+func (e *normalizedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *normalizedError) Unwrap() error {
+	return e.err
+}
+
+func (e *normalizedError) Is(target error) bool {
+	return target == e.target
+}
+
+// normalizeErr makes errors.Is recognize the afero specific errors that stand
+// for one of the standard errors of the fs package.
+func normalizeErr(err error) error {
+	if errors.Is(err, afero.ErrFileClosed) || errors.Is(err, mem.ErrFileClosed) {
+		return &normalizedError{err: err, target: fs.ErrClosed}
+	}
+	return err
+}
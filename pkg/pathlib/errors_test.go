@@ -0,0 +1,138 @@
+package pathlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/aisbergg/go-pathlib/internal/testutils"
+	"github.com/spf13/afero"
+	"github.com/spf13/afero/mem"
+)
+
+func TestPathError(t *testing.T) {
+	for _, afs := range []afero.Fs{afero.NewOsFs(), afero.NewMemMapFs()} {
+		assert := testutils.NewAssert(t)
+		tmpdir := tempDirForTest(t, afs)
+		missing := tmpdir.Join("i_dont_exist")
+
+		_, err := missing.Open()
+		var pathErr *PathError
+		assert.True(errors.As(err, &pathErr))
+		assert.Equal("open", pathErr.Op)
+		assert.Equal(missing.String(), pathErr.Path)
+		assert.Equal(afs, pathErr.Fs)
+		assert.Equal("open "+missing.String()+": "+pathErr.Err.Error(), err.Error())
+
+		_, err = missing.ReadFile()
+		assert.True(errors.As(err, &pathErr))
+		assert.Equal("read", pathErr.Op)
+		assert.True(errors.Is(err, fs.ErrNotExist))
+
+		_, err = missing.Stat()
+		assert.True(errors.Is(err, fs.ErrNotExist))
+		assert.True(errors.Is(missing.Remove(), fs.ErrNotExist))
+
+		_, err = missing.IsEmpty()
+		assert.True(errors.Is(err, fs.ErrNotExist))
+
+		assert.NoError(missing.Mkdir())
+		assert.True(errors.Is(missing.Mkdir(), fs.ErrExist))
+		assert.True(errors.Is(missing.SafeWriteReader(strings.NewReader("")), fs.ErrExist))
+	}
+}
+
+func TestPathErrorSentinels(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	afs := afero.NewMemMapFs()
+
+	_, err := NewPathWithFS(afs, "/").WithName("a")
+	assert.EqualError(ErrEmptyName, err)
+	var pathErr *PathError
+	assert.True(errors.As(err, &pathErr))
+	assert.Equal("withname", pathErr.Op)
+	assert.Equal(afs, pathErr.Fs)
+
+	_, err = NewPathWithFS(afs, "a/b").WithName("c/d")
+	assert.EqualError(ErrInvalidName, err)
+	_, err = NewPathWithFS(afs, "a/b").WithSuffix("c")
+	assert.EqualError(ErrInvalidSuffix, err)
+	_, err = NewPathWithFS(afs, "/a/b").RelativeTo("/c")
+	assert.EqualError(ErrNotRelative, err)
+
+	_, err = NewPurePath("a/b").WithName("")
+	assert.EqualError(ErrInvalidName, err)
+	assert.True(errors.As(err, &pathErr))
+	assert.Equal(nil, pathErr.Fs)
+}
+
+func TestPathErrorWrapped(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	osDir := tempDirForTest(t, afero.NewOsFs())
+	memDir := tempDirForTest(t, NewXattrFs(afero.NewMemMapFs()))
+	file := memDir.Join("file.txt")
+	require.NoError(file.WriteFile([]byte("hello")))
+	manifest := memDir.Join("SUMS")
+	require.NoError(manifest.WriteFile([]byte("not a manifest\n")))
+	plain := tempDirForTest(t, afero.NewMemMapFs()).Join("file.txt")
+	require.NoError(plain.WriteFile([]byte("hello")))
+
+	errOf := func(_ any, err error) error { return err }
+	tests := []struct {
+		name string
+		path Path
+		op   string
+		err  error
+	}{
+		{"xattr unsupported", plain, "xattr", errOf(plain.GetXattr("user.a"))},
+		{"xattr missing", file, "getxattr", errOf(file.GetXattr("user.a"))},
+		{"xattr remove", file, "removexattr", file.RemoveXattr("user.a")},
+		{"xattr missing file", memDir.Join("i_dont_exist"), "", errOf(memDir.Join("i_dont_exist").ListXattr())},
+		{"atime", plain, "stat", errOf(plain.Atime())},
+		{"ctime", plain, "stat", errOf(plain.Ctime())},
+		{"hash", file, "hash", errOf(file.Hash("unknown"))},
+		{"hashtree opts", memDir, "hashtree", errOf(memDir.HashTreeWithOpts(nil))},
+		{"access", osDir.Join("i_dont_exist"), "", errOf(osDir.Join("i_dont_exist").IsReadable())},
+		{"mmap", osDir.Join("i_dont_exist"), "", errOf(osDir.Join("i_dont_exist").Mmap())},
+		{"manifest", manifest, "manifest", errOf(manifest.VerifyManifest(memDir))},
+		{"watch opts", memDir, "watch", errOf(memDir.Watch(context.Background(), nil))},
+		{"encode opts", file, "encode", WriteJSON(file, 1, nil)},
+		{"archive opts", memDir, "archive", memDir.ArchiveTo(file, ArchiveTar, nil)},
+		{"walk opts", memDir, "walk", errOf(NewWalkWithOpts(memDir, nil))},
+		{"follow opts", file, "follow", file.FollowWithOpts(context.Background(), nil, nil)},
+		{"readdir batch", memDir, "readdir", memDir.ReadDirBatch(0, nil)},
+		{"chmod", file, "chmod", file.ChmodSymbolic("u?x")},
+		{"read dir", osDir, "read", errOf(osDir.ReadFileContext(context.Background()))},
+		{"chown by name", plain, "chown", plain.ChownByName("pathlib-no-such-user", "")},
+		{"wrapped", file, "hash", plain.pathError("copy", fmt.Errorf("copying: %w", errOf(file.Hash("unknown"))))},
+	}
+	for _, test := range tests {
+		var pathErr *PathError
+		if !errors.As(test.err, &pathErr) {
+			t.Errorf("%s: expected a PathError, got %v", test.name, test.err)
+			continue
+		}
+		assert.Equal(test.path.String(), pathErr.Path, test.name)
+		if test.op != "" {
+			assert.Equal(test.op, pathErr.Op, test.name)
+		}
+	}
+	_, err := file.GetXattr("user.a")
+	assert.True(errors.Is(err, ErrXattrNotFound))
+	assert.Equal("getxattr "+file.String()+": extended attribute not found: user.a", err.Error())
+}
+
+func TestNormalizeErr(t *testing.T) {
+	assert := testutils.NewAssert(t)
+
+	assert.True(errors.Is(normalizeErr(afero.ErrFileClosed), fs.ErrClosed))
+	assert.True(errors.Is(normalizeErr(mem.ErrFileClosed), fs.ErrClosed))
+	assert.Equal(mem.ErrFileClosed.Error(), normalizeErr(mem.ErrFileClosed).Error())
+
+	other := errors.New("other")
+	assert.Equal(other, normalizeErr(other))
+}
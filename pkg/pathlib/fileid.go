@@ -0,0 +1,77 @@
+package pathlib
+
+import "os"
+
+// FileID identifies a file independently of the path it is reached by. Two
+// paths with the same FileID refer to the same file, e.g. hard links or paths
+// through bind mounts. FileID is comparable and can be used as a map key.
+type FileID struct {
+	// Device is the ID of the device containing the file.
+	Device uint64
+	// Inode is the inode number of the file.
+	Inode uint64
+}
+
+// FileID returns the identity of the file the path points to. Symlinks are
+// followed. This fails with an ErrDoesNotImplement if the underlying afero
+// filesystem does not expose device and inode numbers, which currently only
+// OsFs on Unix systems does.
+func (p Path) FileID() (FileID, error) {
+	info, err := p.Stat()
+	if err != nil {
+		return FileID{}, err
+	}
+	id, ok := fileIDFromInfo(info)
+	if !ok {
+		return FileID{}, p.pathError("fileid", p.doesNotImplementErr("file identities"))
+	}
+	return id, nil
+}
+
+// SameFile returns whether the path and other refer to the same file. Symlinks
+// are followed. If the filesystem exposes file identities (device and inode
+// numbers), they are compared. Otherwise SameFile falls back to DeepEquals.
+func (p Path) SameFile(other Path) (bool, error) {
+	info, err := p.Stat()
+	if err != nil {
+		return false, err
+	}
+	otherInfo, err := other.Stat()
+	if err != nil {
+		return false, err
+	}
+	if same, known := sameFileInfo(info, otherInfo); known {
+		return same, nil
+	}
+	return p.DeepEquals(other)
+}
+
+// sameFileInfo returns whether two os.FileInfo objects describe the same
+// file. The second return value is false if this cannot be determined from
+// the os.FileInfo objects.
+func sameFileInfo(a, b os.FileInfo) (same bool, known bool) {
+	idA, okA := fileIDFromInfo(a)
+	idB, okB := fileIDFromInfo(b)
+	if okA && okB {
+		return idA == idB, true
+	}
+	// os.SameFile only understands the os.FileInfo objects of the os package;
+	// comparing a with itself tells whether it is one of them
+	if a.Sys() != nil && b.Sys() != nil && os.SameFile(a, a) {
+		return os.SameFile(a, b), true
+	}
+	return false, false
+}
+
+// fileIDFromInfo extracts the file identity from the os.FileInfo, if it
+// includes device and inode numbers.
+func fileIDFromInfo(info os.FileInfo) (FileID, bool) {
+	x := NewExtendedFileInfo(info)
+	if !x.Has(StatDevice | StatInode) {
+		return FileID{}, false
+	}
+	return FileID{
+		Device: x.Device,
+		Inode:  x.Inode,
+	}, true
+}
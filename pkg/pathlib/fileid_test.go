@@ -0,0 +1,89 @@
+package pathlib
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/aisbergg/go-pathlib/internal/testutils"
+	"github.com/spf13/afero"
+)
+
+func TestSameFile(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	file := tmpdir.Join("file.txt")
+	require.NoError(file.WriteFile([]byte("hello")))
+	other := tmpdir.Join("other.txt")
+	require.NoError(other.WriteFile([]byte("hello")))
+	hardlink := tmpdir.Join("hardlink.txt")
+	require.NoError(os.Link(file.String(), hardlink.String()))
+	symlink := tmpdir.Join("symlink")
+	require.NoError(symlink.Symlink(file))
+
+	same, err := file.SameFile(hardlink)
+	require.NoError(err)
+	assert.True(same, "expected hard links to be the same file")
+	same, err = file.SameFile(symlink)
+	require.NoError(err)
+	assert.True(same, "expected symlink to be the same file")
+	same, err = file.SameFile(other)
+	require.NoError(err)
+	assert.False(same)
+
+	// hard links are not equal in the lexical sense
+	deepEquals, err := file.DeepEquals(hardlink)
+	require.NoError(err)
+	assert.False(deepEquals)
+
+	_, err = file.SameFile(tmpdir.Join("i_dont_exist"))
+	assert.True(errors.Is(err, fs.ErrNotExist))
+}
+
+func TestSameFileMemMapFs(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	fs := afero.NewMemMapFs()
+	file := NewPathWithFS(fs, "/dir/file.txt")
+	require.NoError(file.Parent().MkdirAll())
+	require.NoError(file.WriteFile([]byte("hello")))
+	other := NewPathWithFS(fs, "/dir/other.txt")
+	require.NoError(other.WriteFile([]byte("hello")))
+
+	same, err := file.SameFile(NewPathWithFS(fs, "/dir/../dir/file.txt"))
+	require.NoError(err)
+	assert.True(same)
+	same, err = file.SameFile(other)
+	require.NoError(err)
+	assert.False(same)
+
+	_, err = file.FileID()
+	assert.True(errors.Is(err, ErrDoesNotImplement))
+}
+
+func TestFileID(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file identities are not supported on Windows")
+	}
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	file := tmpdir.Join("file.txt")
+	require.NoError(file.WriteFile([]byte("hello")))
+	hardlink := tmpdir.Join("hardlink.txt")
+	require.NoError(os.Link(file.String(), hardlink.String()))
+	other := tmpdir.Join("other.txt")
+	require.NoError(other.WriteFile([]byte("hello")))
+
+	ids := map[FileID][]string{}
+	for _, p := range []Path{file, hardlink, other} {
+		id, err := p.FileID()
+		require.NoError(err)
+		ids[id] = append(ids[id], p.Name())
+	}
+	assert.Equal(2, len(ids))
+	fileID, err := file.FileID()
+	require.NoError(err)
+	assert.Equal([]string{"file.txt", "hardlink.txt"}, ids[fileID])
+}
@@ -0,0 +1,197 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+)
+
+// FileType describes the type of a filesystem object.
+type FileType int
+
+const (
+	// FileTypeUnknown is the type of objects that fit none of the other types,
+	// e.g. files that are marked as irregular by the operating system.
+	FileTypeUnknown FileType = iota
+	// FileTypeRegular is the type of regular files.
+	FileTypeRegular
+	// FileTypeDir is the type of directories.
+	FileTypeDir
+	// FileTypeSymlink is the type of symbolic links.
+	FileTypeSymlink
+	// FileTypeFIFO is the type of named pipes.
+	FileTypeFIFO
+	// FileTypeSocket is the type of Unix domain sockets.
+	FileTypeSocket
+	// FileTypeBlockDevice is the type of block devices.
+	FileTypeBlockDevice
+	// FileTypeCharDevice is the type of character devices.
+	FileTypeCharDevice
+)
+
+// String returns a human readable name of the file type.
+func (t FileType) String() string {
+	switch t {
+	case FileTypeRegular:
+		return "regular file"
+	case FileTypeDir:
+		return "directory"
+	case FileTypeSymlink:
+		return "symlink"
+	case FileTypeFIFO:
+		return "fifo"
+	case FileTypeSocket:
+		return "socket"
+	case FileTypeBlockDevice:
+		return "block device"
+	case FileTypeCharDevice:
+		return "char device"
+	default:
+		return "unknown"
+	}
+}
+
+// Type returns the type of the path. Symlinks are not followed, so a symlink
+// is reported as FileTypeSymlink. On filesystems without Lstat support the
+// path is stat-ed instead.
+func (p Path) Type() (FileType, error) {
+	fileInfo, err := p.lstatIfPossible()
+	if err != nil {
+		return FileTypeUnknown, err
+	}
+	return TypeOf(fileInfo.Mode()), nil
+}
+
+// TypeOf returns the type of the file described by the given os.FileMode.
+func TypeOf(mode os.FileMode) FileType {
+	switch {
+	case mode.IsRegular():
+		return FileTypeRegular
+	case mode&os.ModeDir != 0:
+		return FileTypeDir
+	case mode&os.ModeSymlink != 0:
+		return FileTypeSymlink
+	case mode&os.ModeNamedPipe != 0:
+		return FileTypeFIFO
+	case mode&os.ModeSocket != 0:
+		return FileTypeSocket
+	case mode&os.ModeCharDevice != 0:
+		return FileTypeCharDevice
+	case mode&os.ModeDevice != 0:
+		return FileTypeBlockDevice
+	default:
+		return FileTypeUnknown
+	}
+}
+
+// IsFIFO returns true if the given path is a named pipe.
+func (p Path) IsFIFO() (bool, error) {
+	fileInfo, err := p.Stat()
+	if err != nil {
+		return false, err
+	}
+	return IsFIFO(fileInfo.Mode()), nil
+}
+
+// IsFIFO returns true if the file described by the given os.FileMode is a
+// named pipe.
+func IsFIFO(mode os.FileMode) bool {
+	return mode&os.ModeNamedPipe != 0
+}
+
+// IsSocket returns true if the given path is a Unix domain socket.
+func (p Path) IsSocket() (bool, error) {
+	fileInfo, err := p.Stat()
+	if err != nil {
+		return false, err
+	}
+	return IsSocket(fileInfo.Mode()), nil
+}
+
+// IsSocket returns true if the file described by the given os.FileMode is a
+// Unix domain socket.
+func IsSocket(mode os.FileMode) bool {
+	return mode&os.ModeSocket != 0
+}
+
+// IsBlockDevice returns true if the given path is a block device.
+func (p Path) IsBlockDevice() (bool, error) {
+	fileInfo, err := p.Stat()
+	if err != nil {
+		return false, err
+	}
+	return IsBlockDevice(fileInfo.Mode()), nil
+}
+
+// IsBlockDevice returns true if the file described by the given os.FileMode
+// is a block device.
+func IsBlockDevice(mode os.FileMode) bool {
+	return mode&os.ModeDevice != 0 && mode&os.ModeCharDevice == 0
+}
+
+// IsCharDevice returns true if the given path is a character device.
+func (p Path) IsCharDevice() (bool, error) {
+	fileInfo, err := p.Stat()
+	if err != nil {
+		return false, err
+	}
+	return IsCharDevice(fileInfo.Mode()), nil
+}
+
+// IsCharDevice returns true if the file described by the given os.FileMode is
+// a character device.
+func IsCharDevice(mode os.FileMode) bool {
+	return mode&os.ModeCharDevice != 0
+}
+
+// IsMount returns true if the given path is a mount point. Like the mountpoint
+// command, a directory is considered a mount point if it resides on a
+// different device than its parent (path/..), or if it is the same object as
+// its parent (as is the case for the root directory). Bind mounts of a directory within
+// the same filesystem can't be detected this way. Symlinks are never mount
+// points.
+//
+// An ErrStatUnavailable is returned if the filesystem doesn't expose device
+// IDs.
+func (p Path) IsMount() (bool, error) {
+	info, err := p.lstatIfPossible()
+	if err != nil {
+		return false, err
+	}
+	if IsSymlink(info.Mode()) {
+		return false, nil
+	}
+	stat := NewExtendedFileInfo(info)
+	if !stat.Has(StatDevice | StatInode) {
+		return false, p.pathError("ismount", fmt.Errorf("%w: device", ErrStatUnavailable))
+	}
+
+	// the parent is looked up by the filesystem, as the lexical parent is
+	// wrong for relative paths and paths containing ".."
+	parent, err := p.Join("..").StatEx()
+	if err != nil {
+		return false, err
+	}
+	if !parent.Has(StatDevice | StatInode) {
+		return false, p.pathError("ismount", fmt.Errorf("%w: device", ErrStatUnavailable))
+	}
+	if stat.Device != parent.Device {
+		return true, nil
+	}
+	return stat.Inode == parent.Inode, nil
+}
+
+// Mkfifo creates a named pipe at the path. The permission bits default to
+// DefaultFileMode and are subject to the umask of the process.
+//
+// This will fail if the underlying afero filesystem is not OsFs or the platform
+// doesn't support named pipes.
+func (p Path) Mkfifo(perm ...os.FileMode) error {
+	mode := p.DefaultFileMode
+	if len(perm) > 0 {
+		mode = perm[0]
+	}
+	if !isOsFs(p.Fs()) {
+		return p.pathError("mkfifo", p.doesNotImplementErr("Mkfifo"))
+	}
+	return p.pathError("mkfifo", mkfifo(p.String(), mode))
+}
@@ -0,0 +1,13 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package pathlib
+
+import (
+	"os"
+	"syscall"
+)
+
+func mkfifo(name string, mode os.FileMode) error {
+	return &os.PathError{Op: "mkfifo", Path: name, Err: syscall.ENOTSUP}
+}
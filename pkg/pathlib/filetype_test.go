@@ -0,0 +1,152 @@
+package pathlib
+
+import (
+	"errors"
+	"io/fs"
+	"net"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/aisbergg/go-pathlib/internal/testutils"
+	"github.com/spf13/afero"
+)
+
+func TestTypeOf(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	tests := []struct {
+		mode     os.FileMode
+		expected FileType
+	}{
+		{0o644, FileTypeRegular},
+		{os.ModeDir | 0o755, FileTypeDir},
+		{os.ModeSymlink | 0o777, FileTypeSymlink},
+		{os.ModeNamedPipe | 0o644, FileTypeFIFO},
+		{os.ModeSocket | 0o755, FileTypeSocket},
+		{os.ModeDevice | 0o660, FileTypeBlockDevice},
+		{os.ModeDevice | os.ModeCharDevice | 0o666, FileTypeCharDevice},
+		{os.ModeIrregular, FileTypeUnknown},
+	}
+	for _, test := range tests {
+		assert.Equal(test.expected, TypeOf(test.mode), "mode %v", test.mode)
+	}
+	assert.Equal("block device", FileTypeBlockDevice.String())
+	assert.Equal("unknown", FileType(-1).String())
+}
+
+func TestType(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	file := tmpdir.Join("file.txt")
+	require.NoError(file.WriteFile([]byte("hello")))
+	symlink := tmpdir.Join("symlink")
+	require.NoError(symlink.Symlink(file))
+
+	for _, test := range []struct {
+		path     Path
+		expected FileType
+	}{
+		{tmpdir, FileTypeDir},
+		{file, FileTypeRegular},
+		{symlink, FileTypeSymlink},
+	} {
+		fileType, err := test.path.Type()
+		require.NoError(err)
+		assert.Equal(test.expected, fileType, "path %s", test.path)
+	}
+
+	_, err := tmpdir.Join("i_dont_exist").Type()
+	assert.True(errors.Is(err, fs.ErrNotExist))
+}
+
+func TestSpecialFiles(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("named pipes and Unix domain sockets are not supported on Windows")
+	}
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+
+	fifo := tmpdir.Join("fifo")
+	require.NoError(fifo.Mkfifo())
+	isFIFO, err := fifo.IsFIFO()
+	require.NoError(err)
+	assert.True(isFIFO)
+	fileType, err := fifo.Type()
+	require.NoError(err)
+	assert.Equal(FileTypeFIFO, fileType)
+	assert.True(errors.Is(fifo.Mkfifo(), fs.ErrExist))
+
+	socket := tmpdir.Join("socket")
+	listener, err := net.Listen("unix", socket.String())
+	require.NoError(err)
+	defer listener.Close()
+	isSocket, err := socket.IsSocket()
+	require.NoError(err)
+	assert.True(isSocket)
+	isFIFO, err = socket.IsFIFO()
+	require.NoError(err)
+	assert.False(isFIFO)
+
+	devNull := NewPath("/dev/null")
+	isCharDevice, err := devNull.IsCharDevice()
+	require.NoError(err)
+	assert.True(isCharDevice)
+	isBlockDevice, err := devNull.IsBlockDevice()
+	require.NoError(err)
+	assert.False(isBlockDevice)
+
+	// only the regular file is visited
+	require.NoError(tmpdir.Join("file.txt").WriteFile([]byte("hello")))
+	walk, err := NewWalk(tmpdir)
+	require.NoError(err)
+	walk.Opts.VisitFIFOs = false
+	walk.Opts.VisitSockets = false
+	var visited []string
+	require.NoError(walk.Walk(func(path Path, info os.FileInfo, err error) error {
+		visited = append(visited, path.Name())
+		return err
+	}))
+	assert.Equal([]string{"file.txt"}, visited)
+}
+
+func TestMkfifoMemMapFs(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	fifo := NewPathWithFS(afero.NewMemMapFs(), "/fifo")
+	assert.EqualError(ErrDoesNotImplement, fifo.Mkfifo())
+}
+
+func TestIsMount(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("mount points are not detected on Windows")
+	}
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+
+	isMount, err := NewPath("/").IsMount()
+	require.NoError(err)
+	assert.True(isMount)
+
+	dir := tmpdir.Join("dir")
+	require.NoError(dir.Mkdir())
+	isMount, err = dir.IsMount()
+	require.NoError(err)
+	assert.False(isMount)
+
+	// the parent of a relative path is not its lexical parent
+	cwd, err := os.Getwd()
+	require.NoError(err)
+	defer os.Chdir(cwd)
+	require.NoError(os.Chdir(dir.String()))
+	isMount, err = NewPath(".").IsMount()
+	require.NoError(err)
+	assert.False(isMount)
+
+	if runtime.GOOS == "linux" {
+		isMount, err = NewPath("/proc").IsMount()
+		require.NoError(err)
+		assert.True(isMount)
+	}
+
+	_, err = NewPathWithFS(afero.NewMemMapFs(), "/").IsMount()
+	assert.EqualError(ErrStatUnavailable, err)
+}
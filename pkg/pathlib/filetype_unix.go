@@ -0,0 +1,16 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package pathlib
+
+import (
+	"os"
+	"syscall"
+)
+
+func mkfifo(name string, mode os.FileMode) error {
+	if err := syscall.Mkfifo(name, uint32(mode.Perm())); err != nil {
+		return &os.PathError{Op: "mkfifo", Path: name, Err: err}
+	}
+	return nil
+}
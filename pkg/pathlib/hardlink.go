@@ -0,0 +1,107 @@
+package pathlib
+
+import "os"
+
+// HardLinker is implemented by afero filesystems that support hard links.
+type HardLinker interface {
+	Link(oldname, newname string) error
+}
+
+// HardLinkTo makes the path a hard link to the target.
+//
+// This will fail if the underlying afero filesystem is neither OsFs nor
+// implements HardLinker.
+func (p Path) HardLinkTo(target Path) error {
+	if isOsFs(p.Fs()) {
+		return p.pathError("link", os.Link(target.String(), p.String()))
+	}
+	linker, ok := p.Fs().(HardLinker)
+	if !ok {
+		return p.pathError("link", p.doesNotImplementErr("pathlib.HardLinker"))
+	}
+	return p.pathError("link", linker.Link(target.String(), p.String()))
+}
+
+// LinkCount returns the number of hard links to the file. Symlinks are
+// followed.
+//
+// This will fail with an ErrDoesNotImplement if the underlying afero
+// filesystem does not provide link counts.
+func (p Path) LinkCount() (uint64, error) {
+	stat, err := p.StatEx()
+	if err != nil {
+		return 0, err
+	}
+	if !stat.Has(StatNlink) {
+		return 0, p.pathError("linkcount", p.doesNotImplementErr("link counts"))
+	}
+	return stat.Nlink, nil
+}
+
+// HardLinks returns all paths below root that refer to the same file as the
+// path, including the path itself if it is located below root. Symlinks are
+// not followed.
+//
+// This will fail with an ErrDoesNotImplement if the underlying afero
+// filesystem does not provide file identities.
+func (p Path) HardLinks(root Path) ([]Path, error) {
+	id, err := p.FileID()
+	if err != nil {
+		return nil, err
+	}
+	groups, err := root.fileIDGroups()
+	if err != nil {
+		return nil, err
+	}
+	return groups[id], nil
+}
+
+// HardLinkGroups returns all files below the path that have more than one
+// hard link below the path, grouped by their FileID. Symlinks are not
+// followed.
+//
+// This will fail with an ErrDoesNotImplement if the underlying afero
+// filesystem does not provide file identities.
+func (p Path) HardLinkGroups() (map[FileID][]Path, error) {
+	groups, err := p.fileIDGroups()
+	if err != nil {
+		return nil, err
+	}
+	for id, paths := range groups {
+		if len(paths) < 2 {
+			delete(groups, id)
+		}
+	}
+	return groups, nil
+}
+
+// fileIDGroups walks the tree below the path and groups all regular files by
+// their FileID.
+func (p Path) fileIDGroups() (map[FileID][]Path, error) {
+	walk, err := NewWalk(p)
+	if err != nil {
+		return nil, err
+	}
+	walk.Opts.VisitDirs = false
+	walk.Opts.VisitSymlinks = false
+	walk.Opts.VisitFIFOs = false
+	walk.Opts.VisitSockets = false
+	walk.Opts.VisitDevices = false
+
+	groups := map[FileID][]Path{}
+	err = walk.Walk(func(path Path, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		id, ok := fileIDFromInfo(info)
+		if !ok {
+			return path.pathError("fileid", p.doesNotImplementErr("file identities"))
+		}
+		groups[id] = append(groups[id], path)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
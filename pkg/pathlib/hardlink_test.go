@@ -0,0 +1,108 @@
+package pathlib
+
+import (
+	"errors"
+	"runtime"
+	"sort"
+	"testing"
+
+	"github.com/aisbergg/go-pathlib/internal/testutils"
+	"github.com/spf13/afero"
+)
+
+func TestHardLinkTo(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	file := tmpdir.Join("file.txt")
+	require.NoError(file.WriteFile([]byte("hello")))
+	link := tmpdir.Join("link.txt")
+	require.NoError(link.HardLinkTo(file))
+
+	content, err := link.ReadFile()
+	require.NoError(err)
+	assert.Equal([]byte("hello"), content)
+	same, err := link.SameFile(file)
+	require.NoError(err)
+	assert.True(same)
+
+	assert.Error(link.HardLinkTo(file), "expected an error for an existing path")
+}
+
+func TestLinkCount(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("link counts are not supported on Windows")
+	}
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	file := tmpdir.Join("file.txt")
+	require.NoError(file.WriteFile([]byte("hello")))
+
+	count, err := file.LinkCount()
+	require.NoError(err)
+	assert.Equal(uint64(1), count)
+
+	require.NoError(tmpdir.Join("link1.txt").HardLinkTo(file))
+	require.NoError(tmpdir.Join("link2.txt").HardLinkTo(file))
+	count, err = file.LinkCount()
+	require.NoError(err)
+	assert.Equal(uint64(3), count)
+}
+
+func TestHardLinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file identities are not supported on Windows")
+	}
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	require.NoError(TwoFilesAtRootTwoInSubdir(tmpdir))
+	file := tmpdir.Join("file0.txt")
+	require.NoError(tmpdir.Join("subdir", "link.txt").HardLinkTo(file))
+	require.NoError(tmpdir.Join("symlink").Symlink(file))
+	other := tmpdir.Join("file1.txt")
+	require.NoError(tmpdir.Join("other-link.txt").HardLinkTo(other))
+
+	links, err := file.HardLinks(tmpdir)
+	require.NoError(err)
+	names := make([]string, 0, len(links))
+	for _, link := range links {
+		rel, err := link.RelativeToPath(tmpdir)
+		require.NoError(err)
+		names = append(names, rel.AsPosix())
+	}
+	sort.Strings(names)
+	assert.Equal([]string{"file0.txt", "subdir/link.txt"}, names)
+
+	links, err = file.HardLinks(tmpdir.Join("subdir"))
+	require.NoError(err)
+	require.Equal(1, len(links))
+	assert.Equal("link.txt", links[0].Name())
+
+	// only regular files are grouped
+	fifo := tmpdir.Join("fifo")
+	require.NoError(fifo.Mkfifo())
+	require.NoError(tmpdir.Join("fifo-link").HardLinkTo(fifo))
+
+	groups, err := tmpdir.HardLinkGroups()
+	require.NoError(err)
+	assert.Equal(2, len(groups))
+	for _, group := range groups {
+		assert.Equal(2, len(group))
+	}
+}
+
+func TestHardLinksMemMapFs(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	root := NewPathWithFS(afero.NewMemMapFs(), "/root")
+	require.NoError(root.MkdirAll())
+	file := root.Join("file.txt")
+	require.NoError(file.WriteFile([]byte("hello")))
+
+	assert.True(errors.Is(root.Join("link.txt").HardLinkTo(file), ErrDoesNotImplement))
+	_, err := file.LinkCount()
+	assert.True(errors.Is(err, ErrDoesNotImplement))
+	_, err = file.HardLinks(root)
+	assert.True(errors.Is(err, ErrDoesNotImplement))
+	_, err = root.HardLinkGroups()
+	assert.True(errors.Is(err, ErrDoesNotImplement))
+}
@@ -0,0 +1,249 @@
+package pathlib
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// HashAlgorithm is the name of a hash algorithm that can be used with Hash and
+// HashTree.
+type HashAlgorithm string
+
+const (
+	// HashSHA256 is the SHA-256 hash algorithm.
+	HashSHA256 HashAlgorithm = "sha256"
+	// HashSHA1 is the SHA-1 hash algorithm.
+	HashSHA1 HashAlgorithm = "sha1"
+	// HashMD5 is the MD5 hash algorithm.
+	HashMD5 HashAlgorithm = "md5"
+	// HashCRC32 is the CRC-32 checksum using the IEEE polynomial.
+	HashCRC32 HashAlgorithm = "crc32"
+)
+
+var (
+	hashFactoriesMu sync.RWMutex
+	hashFactories   = map[HashAlgorithm]func() hash.Hash{
+		HashSHA256: sha256.New,
+		HashSHA1:   sha1.New,
+		HashMD5:    md5.New,
+		HashCRC32:  func() hash.Hash { return crc32.NewIEEE() },
+	}
+)
+
+// RegisterHashAlgorithm registers a factory for the given hash algorithm,
+// making it available to Hash and HashTree. Registering an already known
+// algorithm replaces its factory.
+func RegisterHashAlgorithm(alg HashAlgorithm, factory func() hash.Hash) {
+	hashFactoriesMu.Lock()
+	defer hashFactoriesMu.Unlock()
+	hashFactories[alg] = factory
+}
+
+// NewHash returns a new hash.Hash for the given algorithm.
+func NewHash(alg HashAlgorithm) (hash.Hash, error) {
+	hashFactoriesMu.RLock()
+	factory, ok := hashFactories[alg]
+	hashFactoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownHashAlgorithm, alg)
+	}
+	return factory(), nil
+}
+
+// isHashAlgorithm returns whether a factory is registered for the algorithm.
+func isHashAlgorithm(alg HashAlgorithm) bool {
+	hashFactoriesMu.RLock()
+	defer hashFactoriesMu.RUnlock()
+	_, ok := hashFactories[alg]
+	return ok
+}
+
+// Hash returns the digest of the file's content using the given algorithm.
+func (p Path) Hash(alg HashAlgorithm) ([]byte, error) {
+	h, err := NewHash(alg)
+	if err != nil {
+		return nil, p.pathError("hash", err)
+	}
+	if err := p.hashContent(h); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// hashContent writes the content of the file to the given hash.
+func (p Path) hashContent(h hash.Hash) error {
+	file, err := p.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(h, file)
+	return p.pathError("read", err)
+}
+
+// HashTreeOpts is the struct that defines how the digest of a directory tree
+// is computed.
+type HashTreeOpts struct {
+	// Algorithm is the hash algorithm used for all nodes of the tree.
+	Algorithm HashAlgorithm
+
+	// IgnoreMode specifies that the file modes are not part of the digest.
+	IgnoreMode bool
+
+	// IgnoreMtime specifies that the modification times are not part of the
+	// digest.
+	IgnoreMtime bool
+
+	// Exclude is a list of patterns; files and directories whose path relative
+	// to the root matches any of them (see PurePath.Match) are left out of the
+	// digest. Excluded directories are not descended into.
+	Exclude []string
+}
+
+// DefaultHashTreeOpts returns the default HashTreeOpts struct used when
+// computing the digest of a directory tree.
+func DefaultHashTreeOpts() *HashTreeOpts {
+	return &HashTreeOpts{
+		Algorithm:   HashSHA256,
+		IgnoreMode:  false,
+		IgnoreMtime: true,
+		Exclude:     nil,
+	}
+}
+
+// HashTree computes a deterministic digest of the directory tree rooted at the
+// path using the default options. See HashTreeWithOpts for details.
+func (p Path) HashTree() ([]byte, error) {
+	return p.HashTreeWithOpts(DefaultHashTreeOpts())
+}
+
+// HashTreeWithOpts computes a deterministic digest of the tree rooted at the
+// path in the style of a Merkle tree: the digest of a file covers its content,
+// the digest of a symlink its target and the digest of a directory the sorted
+// names and digests of its children. Modes and modification times are included
+// depending on the options. The name of the root itself is not part of the
+// digest. Symlinks are not followed.
+func (p Path) HashTreeWithOpts(opts *HashTreeOpts) ([]byte, error) {
+	if opts == nil {
+		return nil, p.pathError("hashtree", fmt.Errorf("opts can't be nil"))
+	}
+	if _, err := NewHash(opts.Algorithm); err != nil {
+		return nil, p.pathError("hashtree", err)
+	}
+	info, err := p.lstatIfPossible()
+	if err != nil {
+		return nil, err
+	}
+	return p.hashTreeNode(p, info, opts)
+}
+
+// hashTreeNode computes the digest of a single node of the tree.
+func (p Path) hashTreeNode(root Path, info os.FileInfo, opts *HashTreeOpts) ([]byte, error) {
+	h, _ := NewHash(opts.Algorithm)
+	mode := info.Mode()
+
+	// header: node type, mode and mtime
+	var header [17]byte
+	switch {
+	case IsSymlink(mode):
+		header[0] = 'l'
+	case IsDir(mode):
+		header[0] = 'd'
+	case IsFile(mode):
+		header[0] = 'f'
+	default:
+		header[0] = '?'
+	}
+	if !opts.IgnoreMode {
+		binary.BigEndian.PutUint64(header[1:9], uint64(mode&(os.ModePerm|os.ModeSetuid|os.ModeSetgid|os.ModeSticky)))
+	}
+	if !opts.IgnoreMtime && !IsDir(mode) {
+		binary.BigEndian.PutUint64(header[9:17], uint64(info.ModTime().UnixNano()))
+	}
+	h.Write(header[:])
+
+	switch {
+	case IsSymlink(mode):
+		target, err := p.Readlink()
+		if err != nil {
+			return nil, err
+		}
+		h.Write([]byte(target.String()))
+
+	case IsDir(mode):
+		children, err := p.ReadDir()
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(children, func(i, j int) bool {
+			return children[i].Name() < children[j].Name()
+		})
+		for _, child := range children {
+			excluded, err := child.matchesAny(root, opts.Exclude)
+			if err != nil {
+				return nil, err
+			}
+			if excluded {
+				continue
+			}
+			childInfo, err := child.lstatIfPossible()
+			if err != nil {
+				return nil, err
+			}
+			digest, err := child.hashTreeNode(root, childInfo, opts)
+			if err != nil {
+				return nil, err
+			}
+			h.Write([]byte(child.Name()))
+			h.Write([]byte{0})
+			h.Write(digest)
+		}
+
+	case IsFile(mode):
+		content, _ := NewHash(opts.Algorithm)
+		if err := p.hashContent(content); err != nil {
+			return nil, err
+		}
+		h.Write(content.Sum(nil))
+	}
+
+	return h.Sum(nil), nil
+}
+
+// matchesAny returns whether the path relative to root matches any of the
+// given patterns.
+func (p Path) matchesAny(root Path, patterns []string) (bool, error) {
+	if len(patterns) == 0 {
+		return false, nil
+	}
+	rel, err := p.RelativeToPath(root)
+	if err != nil {
+		return false, err
+	}
+	for _, pattern := range patterns {
+		if rel.Match(pattern) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// lstatIfPossible lstat's the path if the underlying filesystem supports it and
+// falls back to stat otherwise.
+func (p Path) lstatIfPossible() (os.FileInfo, error) {
+	info, err := p.Lstat()
+	if errors.Is(err, ErrDoesNotImplement) || errors.Is(err, ErrLstatNotPossible) {
+		return p.Stat()
+	}
+	return info, err
+}
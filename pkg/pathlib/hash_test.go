@@ -0,0 +1,160 @@
+package pathlib
+
+import (
+	"encoding/hex"
+	"errors"
+	"hash"
+	"hash/adler32"
+	"io/fs"
+	"testing"
+	"time"
+
+	"github.com/aisbergg/go-pathlib/internal/testutils"
+	"github.com/spf13/afero"
+)
+
+func TestHash(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	file := NewPathWithFS(afero.NewMemMapFs(), "/file.txt")
+	require.NoError(file.WriteFile([]byte("hello world")))
+
+	tests := []struct {
+		alg      HashAlgorithm
+		expected string
+	}{
+		{HashSHA256, "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"},
+		{HashSHA1, "2aae6c35c94fcfb415dbe95f408b9ce91ee846ed"},
+		{HashMD5, "5eb63bbbe01eeed093cb22bb8f5acdc3"},
+		{HashCRC32, "0d4a1185"},
+	}
+	for _, test := range tests {
+		digest, err := file.Hash(test.alg)
+		require.NoError(err)
+		assert.Equal(test.expected, hex.EncodeToString(digest), "algorithm %s", test.alg)
+	}
+
+	_, err := file.Hash("unknown")
+	assert.EqualError(ErrUnknownHashAlgorithm, err)
+}
+
+func TestRegisterHashAlgorithm(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	file := NewPathWithFS(afero.NewMemMapFs(), "/file.txt")
+	require.NoError(file.WriteFile([]byte("hello world")))
+
+	RegisterHashAlgorithm("adler32", func() hash.Hash { return adler32.New() })
+	digest, err := file.Hash("adler32")
+	require.NoError(err)
+	assert.Equal("1a0b045d", hex.EncodeToString(digest))
+}
+
+func setupHashTree(t *testing.T, root Path) {
+	require := testutils.NewRequire(t)
+	require.NoError(TwoFilesAtRootTwoInSubdir(root))
+	require.NoError(root.Join("subdir", "nested").MkdirAll())
+	require.NoError(OneFile(root.Join("subdir", "nested"), "deep.txt", "deep"))
+}
+
+func TestHashTree(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	a := tmpdir.Join("a")
+	b := tmpdir.Join("b")
+	require.NoError(a.Mkdir())
+	require.NoError(b.Mkdir())
+	setupHashTree(t, a)
+	setupHashTree(t, b)
+
+	digestA, err := a.HashTree()
+	require.NoError(err)
+	digestB, err := b.HashTree()
+	require.NoError(err)
+	assert.Equal(digestA, digestB)
+
+	// content changes
+	require.NoError(OneFile(b.Join("subdir", "nested"), "deep.txt", "changed"))
+	digestB, err = b.HashTree()
+	require.NoError(err)
+	assert.False(string(digestA) == string(digestB), "expected digest to change with content")
+	require.NoError(OneFile(b.Join("subdir", "nested"), "deep.txt", "deep"))
+
+	// symlinks are hashed by their target
+	require.NoError(a.Join("link").Symlink(NewPath("file0.txt")))
+	require.NoError(b.Join("link").Symlink(NewPath("file1.txt")))
+	digestA, err = a.HashTree()
+	require.NoError(err)
+	digestB, err = b.HashTree()
+	require.NoError(err)
+	assert.False(string(digestA) == string(digestB), "expected digest to change with symlink target")
+}
+
+func TestHashTreeOpts(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	a := tmpdir.Join("a")
+	b := tmpdir.Join("b")
+	require.NoError(a.Mkdir())
+	require.NoError(b.Mkdir())
+	setupHashTree(t, a)
+	setupHashTree(t, b)
+
+	// modes
+	require.NoError(b.Join("file0.txt").Chmod(0o600))
+	opts := DefaultHashTreeOpts()
+	digestA, err := a.HashTreeWithOpts(opts)
+	require.NoError(err)
+	digestB, err := b.HashTreeWithOpts(opts)
+	require.NoError(err)
+	assert.False(string(digestA) == string(digestB), "expected digest to change with mode")
+	opts.IgnoreMode = true
+	digestA, err = a.HashTreeWithOpts(opts)
+	require.NoError(err)
+	digestB, err = b.HashTreeWithOpts(opts)
+	require.NoError(err)
+	assert.Equal(digestA, digestB)
+
+	// mtimes
+	require.NoError(b.Join("file1.txt").Chtimes(time.Unix(0, 0), time.Unix(0, 0)))
+	digestB, err = b.HashTreeWithOpts(opts)
+	require.NoError(err)
+	assert.Equal(digestA, digestB)
+	opts.IgnoreMtime = false
+	digestA, err = a.HashTreeWithOpts(opts)
+	require.NoError(err)
+	digestB, err = b.HashTreeWithOpts(opts)
+	require.NoError(err)
+	assert.False(string(digestA) == string(digestB), "expected digest to change with mtime")
+
+	// excludes
+	opts = DefaultHashTreeOpts()
+	opts.Exclude = []string{"*.log", "subdir/nested"}
+	digestA, err = a.HashTreeWithOpts(opts)
+	require.NoError(err)
+	require.NoError(OneFile(a, "debug.log", "noise"))
+	require.NoError(OneFile(a.Join("subdir", "nested"), "other.txt", "noise"))
+	digestB, err = a.HashTreeWithOpts(opts)
+	require.NoError(err)
+	assert.Equal(digestA, digestB)
+}
+
+func TestHashTreeMemMapFs(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	a := NewPathWithFS(afero.NewMemMapFs(), "/root")
+	b := NewPathWithFS(afero.NewMemMapFs(), "/other/root")
+	require.NoError(a.MkdirAll())
+	require.NoError(b.MkdirAll())
+	setupHashTree(t, a)
+	setupHashTree(t, b)
+
+	digestA, err := a.HashTree()
+	require.NoError(err)
+	digestB, err := b.HashTree()
+	require.NoError(err)
+	assert.Equal(digestA, digestB)
+
+	_, err = a.Join("i_dont_exist").HashTree()
+	assert.True(errors.Is(err, fs.ErrNotExist))
+}
@@ -0,0 +1,190 @@
+package pathlib
+
+import (
+	"iter"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// IterDir returns an iterator over the entries of the directory. The entries
+// are read in batches like ReadDirIter does and the directory handle is closed
+// once the iteration ends, also if the loop is left early. An error is
+// yielded with an empty path and ends the iteration.
+func (p Path) IterDir() iter.Seq2[Path, error] {
+	return func(yield func(Path, error) bool) {
+		it, err := p.ReadDirIter()
+		if err != nil {
+			yield(Path{}, err)
+			return
+		}
+		defer it.Close()
+		for it.Next() {
+			if !yield(it.Path(), nil) {
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			yield(Path{}, err)
+		}
+	}
+}
+
+// Walk returns an iterator over the tree below the path, using the default
+// WalkOpts. See WalkWithOpts for details.
+func (p Path) Walk() iter.Seq2[Path, error] {
+	return p.WalkWithOpts(DefaultWalkOpts())
+}
+
+// WalkWithOpts returns an iterator over the tree below the path, visiting the
+// objects in the order of Walk.Walk. Leaving the loop early stops the walk,
+// there is no need to return ErrStopWalk. Errors encountered for single
+// objects are yielded together with their path; an error that stops the walk
+// is yielded with an empty path.
+func (p Path) WalkWithOpts(opts *WalkOpts) iter.Seq2[Path, error] {
+	return func(yield func(Path, error) bool) {
+		walk, err := NewWalkWithOpts(p, opts)
+		if err != nil {
+			yield(Path{}, err)
+			return
+		}
+		stopped := false
+		err = walk.Walk(func(path Path, info os.FileInfo, err error) error {
+			if !yield(path, err) {
+				stopped = true
+				return ErrStopWalk
+			}
+			return nil
+		})
+		if err != nil && !stopped {
+			yield(Path{}, err)
+		}
+	}
+}
+
+// IterGlob returns an iterator over the matches of pattern relative to the
+// path, like Glob. The matches are yielded while the directories are read, in
+// the order the filesystem lists them. A malformed pattern is yielded as an
+// error with an empty path and ends the iteration.
+func (p Path) IterGlob(pattern string) iter.Seq2[Path, error] {
+	return func(yield func(Path, error) bool) {
+		pattern := strings.Join([]string{p.String(), pattern}, "/")
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			yield(Path{}, p.pathError("glob", err))
+			return
+		}
+		p.glob(pattern, yield)
+	}
+}
+
+// glob yields the matches of the pattern. It returns false if the iteration
+// should stop.
+func (p Path) glob(pattern string, yield func(Path, error) bool) bool {
+	if !globHasMeta(pattern) {
+		match := copyPathWithPaths(p, pattern)
+		if _, err := match.lstatIfPossible(); err != nil {
+			return true
+		}
+		return yield(match, nil)
+	}
+
+	dir, file := filepath.Split(pattern)
+	dir = cleanGlobDir(dir)
+	if !globHasMeta(dir) {
+		return p.globDir(copyPathWithPaths(p, dir), file, yield)
+	}
+	return p.glob(dir, func(match Path, err error) bool {
+		if err != nil {
+			return yield(Path{}, err)
+		}
+		return p.globDir(match, file, yield)
+	})
+}
+
+// globDir yields the entries of the directory that match the pattern. It
+// returns false if the iteration should stop.
+func (p Path) globDir(dir Path, pattern string, yield func(Path, error) bool) bool {
+	info, err := dir.Stat()
+	if err != nil || !info.IsDir() {
+		return true
+	}
+	it, err := dir.ReadDirIter()
+	if err != nil {
+		return true
+	}
+	defer it.Close()
+	for it.Next() {
+		matched, err := filepath.Match(pattern, it.Path().Name())
+		if err != nil {
+			yield(Path{}, p.pathError("glob", err))
+			return false
+		}
+		if matched && !yield(it.Path(), nil) {
+			return false
+		}
+	}
+	if err := it.Err(); err != nil {
+		return yield(Path{}, err)
+	}
+	return true
+}
+
+// globHasMeta reports whether the path contains any of the magic characters
+// recognized by filepath.Match.
+func globHasMeta(path string) bool {
+	magicChars := `*?[`
+	if runtime.GOOS != "windows" {
+		magicChars = `*?[\`
+	}
+	return strings.ContainsAny(path, magicChars)
+}
+
+// cleanGlobDir prepares the directory part of a pattern for globbing.
+func cleanGlobDir(path string) string {
+	vollen := len(filepath.VolumeName(path))
+	switch {
+	case path == "":
+		return "."
+	case vollen+1 == len(path) && os.IsPathSeparator(path[len(path)-1]):
+		// /, \, C:\ and C:/
+		return path
+	case vollen == len(path) && len(path) == 2:
+		// C: => C:.
+		return path + "."
+	default:
+		// remove trailing separator
+		return path[:len(path)-1]
+	}
+}
+
+// Ancestors returns an iterator over the parent directories of the path,
+// starting with the immediate parent. It yields the same paths as Parents,
+// without building a slice.
+func (p PurePath) Ancestors() iter.Seq[PurePath] {
+	return func(yield func(PurePath) bool) {
+		drive, root, parts := p.drive, p.root, p.parts
+		last := 0
+		if drive != "" || root != "" {
+			last = 1
+		}
+		for i := len(parts) - 1; i >= last; i-- {
+			if !yield(newPurePathFromParts(p.flavor, drive, root, parts[:i])) {
+				return
+			}
+		}
+	}
+}
+
+// Ancestors returns an iterator over the parent directories of the path,
+// starting with the immediate parent. It yields the same paths as Parents,
+// without building a slice.
+func (p Path) Ancestors() iter.Seq[Path] {
+	return func(yield func(Path) bool) {
+		for parent := range p.PurePath.Ancestors() {
+			if !yield(copyPathWithPurePath(p, parent)) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package pathlib
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/aisbergg/go-pathlib/internal/testutils"
+	"github.com/spf13/afero"
+)
+
+func TestIterDir(t *testing.T) {
+	for _, afs := range []afero.Fs{afero.NewOsFs(), afero.NewMemMapFs()} {
+		assert := testutils.NewAssert(t)
+		require := testutils.NewRequire(t)
+		root := tempDirForTest(t, afs)
+		require.NoError(TwoFilesAtRootTwoInSubdir(root))
+
+		var found []string
+		for path, err := range root.IterDir() {
+			require.NoError(err)
+			found = append(found, path.Name())
+		}
+		sort.Strings(found)
+		assert.Equal([]string{"file0.txt", "file1.txt", "subdir"}, found)
+
+		count := 0
+		for range root.IterDir() {
+			count++
+			break
+		}
+		assert.Equal(1, count)
+
+		for _, err := range root.Join("i_dont_exist").IterDir() {
+			assert.True(errors.Is(err, fs.ErrNotExist))
+		}
+	}
+}
+
+func TestIterDirClosesHandle(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	afs, dir := setupReadDirTest(t, 3)
+	for range dir.IterDir() {
+		break
+	}
+	assert.Equal(int64(0), afs.openHandles())
+}
+
+func TestPathWalk(t *testing.T) {
+	for _, afs := range []afero.Fs{afero.NewOsFs(), afero.NewMemMapFs()} {
+		assert := testutils.NewAssert(t)
+		require := testutils.NewRequire(t)
+		root := tempDirForTest(t, afs)
+		require.NoError(TwoFilesAtRootTwoInSubdir(root))
+
+		var found []string
+		for path, err := range root.Walk() {
+			require.NoError(err)
+			rel, err := path.RelativeTo(root.String())
+			require.NoError(err)
+			found = append(found, rel.String())
+		}
+		sort.Strings(found)
+		assert.Equal([]string{"file0.txt", "file1.txt", "subdir", "subdir/file0.txt", "subdir/file1.txt"}, found)
+
+		opts := DefaultWalkOpts()
+		opts.VisitDirs = false
+		count := 0
+		for path, err := range root.WalkWithOpts(opts) {
+			require.NoError(err)
+			assert.False(path.Name() == "subdir")
+			count++
+			if count == 2 {
+				break
+			}
+		}
+		assert.Equal(2, count)
+
+		for _, err := range root.WalkWithOpts(nil) {
+			assert.Error(err)
+		}
+	}
+}
+
+func TestIterGlob(t *testing.T) {
+	for _, afs := range []afero.Fs{afero.NewOsFs(), afero.NewMemMapFs()} {
+		assert := testutils.NewAssert(t)
+		require := testutils.NewRequire(t)
+		root := tempDirForTest(t, afs)
+		require.NoError(TwoFilesAtRootTwoInSubdir(root))
+
+		var found []Path
+		for path, err := range root.IterGlob("*/file*.txt") {
+			require.NoError(err)
+			found = append(found, path)
+		}
+		sort.Slice(found, func(i, j int) bool { return found[i].String() < found[j].String() })
+		expected, err := root.Glob("*/file*.txt")
+		require.NoError(err)
+		assert.Equal(expected, found)
+
+		found = nil
+		for path, err := range root.IterGlob("file0.txt") {
+			require.NoError(err)
+			found = append(found, path)
+		}
+		assert.Equal([]Path{root.Join("file0.txt")}, found)
+
+		for range root.IterGlob("i_dont_exist") {
+			t.Error("no match expected")
+		}
+
+		for _, err := range root.IterGlob("[") {
+			assert.True(errors.Is(err, filepath.ErrBadPattern))
+		}
+	}
+}
+
+func TestAncestors(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	for _, path := range []string{"/a/b/c", "a/b/c", "/", "a", ""} {
+		var ancestors []PurePath
+		for parent := range NewPurePath(path).Ancestors() {
+			ancestors = append(ancestors, parent)
+		}
+		assert.Equal(len(NewPurePath(path).Parents()), len(ancestors), path)
+		for i, parent := range NewPurePath(path).Parents() {
+			assert.Equal(parent.String(), ancestors[i].String(), path)
+		}
+	}
+
+	var ancestors []string
+	for parent := range NewPath("/a/b/c").Ancestors() {
+		ancestors = append(ancestors, parent.String())
+		if parent.String() == "/a" {
+			break
+		}
+	}
+	assert.Equal([]string{"/a/b", "/a"}, ancestors)
+}
@@ -0,0 +1,331 @@
+package pathlib
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// tailChunkSize is the number of bytes read per step when Tail scans a file
+// backwards.
+const tailChunkSize = 4096
+
+// Scanner reads a file token by token (line by line by default). It embeds a
+// bufio.Scanner, so the split function and buffer can be adjusted before the
+// first call to Scan. The Scanner must be closed to release the underlying
+// file.
+type Scanner struct {
+	*bufio.Scanner
+	file *File
+}
+
+// Close closes the underlying file.
+func (s *Scanner) Close() error {
+	return s.file.Close()
+}
+
+// Scanner opens the path for reading and returns a Scanner that splits the
+// content into lines. maxTokenSize is the maximum size of a single line; longer
+// lines cause Scan to stop with bufio.ErrTooLong. It must be positive.
+func (p Path) Scanner(maxTokenSize int) (*Scanner, error) {
+	if maxTokenSize <= 0 {
+		return nil, p.pathError("scan", fmt.Errorf("invalid max token size %d", maxTokenSize))
+	}
+	file, err := p.Open()
+	if err != nil {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(file)
+	initSize := 4096
+	if maxTokenSize < initSize {
+		initSize = maxTokenSize
+	}
+	scanner.Buffer(make([]byte, 0, initSize), maxTokenSize)
+	return &Scanner{
+		Scanner: scanner,
+		file:    file,
+	}, nil
+}
+
+// Lines opens the path for reading and returns a Scanner that iterates over
+// its lines without reading the whole file into memory. Line endings ("\n" and
+// "\r\n") are stripped. Lines may be at most bufio.MaxScanTokenSize long, use
+// Scanner to read files with longer lines.
+func (p Path) Lines() (*Scanner, error) {
+	return p.Scanner(bufio.MaxScanTokenSize)
+}
+
+// Head returns the first n lines of the file. If the file has fewer lines, all
+// lines are returned.
+func (p Path) Head(n int) ([]string, error) {
+	if n <= 0 {
+		return []string{}, nil
+	}
+	scanner, err := p.Lines()
+	if err != nil {
+		return nil, err
+	}
+	defer scanner.Close()
+
+	lines := make([]string, 0, n)
+	for len(lines) < n && scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, p.pathError("read", scanner.Err())
+}
+
+// Tail returns the last n lines of the file. The file is read backwards
+// starting from its end, so only the required portion of the file is read.
+func (p Path) Tail(n int) ([]string, error) {
+	if n <= 0 {
+		return []string{}, nil
+	}
+	file, err := p.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	pos, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, p.pathError("seek", err)
+	}
+
+	// read chunks from the end until we have seen enough line breaks or have
+	// reached the beginning of the file; a line break at the very end does not
+	// start another line
+	var chunks [][]byte
+	breaks := 0
+	for pos > 0 && breaks < n {
+		size := int64(tailChunkSize)
+		if pos < size {
+			size = pos
+		}
+		pos -= size
+		chunk := make([]byte, size)
+		if _, err := file.ReadAt(chunk, pos); err != nil && !errors.Is(err, io.EOF) {
+			return nil, p.pathError("read", err)
+		}
+		if len(chunks) == 0 {
+			breaks -= bytes.Count(chunk[len(chunk)-1:], []byte("\n"))
+		}
+		breaks += bytes.Count(chunk, []byte("\n"))
+		chunks = append(chunks, chunk)
+	}
+	for i, j := 0, len(chunks)-1; i < j; i, j = i+1, j-1 {
+		chunks[i], chunks[j] = chunks[j], chunks[i]
+	}
+	data := bytes.Join(chunks, nil)
+
+	data = bytes.TrimSuffix(data, []byte("\n"))
+	if len(data) == 0 {
+		return []string{}, nil
+	}
+	split := bytes.Split(data, []byte("\n"))
+	if len(split) > n {
+		split = split[len(split)-n:]
+	}
+	lines := make([]string, 0, len(split))
+	for _, line := range split {
+		lines = append(lines, string(bytes.TrimSuffix(line, []byte("\r"))))
+	}
+	return lines, nil
+}
+
+// -----------------------------------------------------------------------------
+//
+// Follow
+//
+// -----------------------------------------------------------------------------
+
+// LineFunc is the function provided to Follow for each line.
+type LineFunc func(line string) error
+
+// FollowOpts is the struct that defines how a file should be followed.
+type FollowOpts struct {
+	// PollInterval specifies how often the file is checked for new data. A
+	// value of 0 or less stands for the interval of DefaultFollowOpts.
+	PollInterval time.Duration
+
+	// FromStart specifies that the existing content of the file should be
+	// passed to the LineFunc as well. If false, only lines appended after
+	// Follow was called are passed on.
+	FromStart bool
+}
+
+// DefaultFollowOpts returns the default FollowOpts struct used when following
+// a file.
+func DefaultFollowOpts() *FollowOpts {
+	return &FollowOpts{
+		PollInterval: 250 * time.Millisecond,
+		FromStart:    false,
+	}
+}
+
+// Follow behaves like `tail -F`: it calls fn for every line appended to the
+// file until the context is cancelled or fn returns an error. Follow survives
+// truncation of the file (reading continues at the new end) as well as log
+// rotation, where the file is moved away and recreated (the new file is read
+// from its start). The file does not need to exist when Follow is called.
+//
+// Detecting rotation requires the filesystem to expose file identities (as
+// OsFs does); on other filesystems only truncation is detected.
+func (p Path) Follow(ctx context.Context, fn LineFunc) error {
+	return p.FollowWithOpts(ctx, DefaultFollowOpts(), fn)
+}
+
+// FollowWithOpts is like Follow but with the given FollowOpts applied.
+func (p Path) FollowWithOpts(ctx context.Context, opts *FollowOpts, fn LineFunc) error {
+	if opts == nil {
+		return p.pathError("follow", fmt.Errorf("opts can't be nil"))
+	}
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = DefaultFollowOpts().PollInterval
+	}
+	f := &follower{
+		path:      p,
+		fn:        fn,
+		fromStart: opts.FromStart,
+	}
+	defer f.close()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := f.poll(); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return p.pathError("follow", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// follower holds the state of a Follow call.
+type follower struct {
+	path Path
+	fn   LineFunc
+
+	file      *File
+	info      os.FileInfo
+	offset    int64
+	partial   []byte
+	fromStart bool
+}
+
+func (f *follower) close() {
+	if f.file != nil {
+		f.file.Close()
+		f.file = nil
+	}
+}
+
+// poll reads any new data and handles truncation and rotation of the file.
+func (f *follower) poll() error {
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				// everything in a file created later on is new
+				f.fromStart = true
+				return nil
+			}
+			return err
+		}
+	}
+
+	if err := f.read(); err != nil {
+		return err
+	}
+
+	info, err := f.path.Stat()
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			// the file was moved away; keep the old handle until a new file
+			// appears
+			return nil
+		}
+		return err
+	}
+	if same, known := sameFileInfo(f.info, info); known && !same {
+		// rotated: the remaining data of the old file was read above
+		f.close()
+		f.fromStart = true
+		if err := f.open(); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		return f.read()
+	}
+	if info.Size() < f.offset {
+		// truncated
+		f.offset = 0
+		f.partial = nil
+		return f.read()
+	}
+	return nil
+}
+
+// open opens the file and positions the offset according to fromStart.
+func (f *follower) open() error {
+	file, err := f.path.Open()
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return f.path.pathError("stat", err)
+	}
+	f.file = file
+	f.info = info
+	f.partial = nil
+	f.offset = 0
+	if !f.fromStart {
+		f.offset = info.Size()
+	}
+	return nil
+}
+
+// read reads all data after the current offset and passes complete lines to
+// the LineFunc.
+func (f *follower) read() error {
+	buf := make([]byte, tailChunkSize)
+	for {
+		n, err := f.file.ReadAt(buf, f.offset)
+		if n > 0 {
+			f.offset += int64(n)
+			f.partial = append(f.partial, buf[:n]...)
+			for {
+				i := bytes.IndexByte(f.partial, '\n')
+				if i < 0 {
+					break
+				}
+				line := bytes.TrimSuffix(f.partial[:i], []byte("\r"))
+				f.partial = f.partial[i+1:]
+				if err := f.fn(string(line)); err != nil {
+					return err
+				}
+			}
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return f.path.pathError("read", err)
+		}
+		if n == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package pathlib
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aisbergg/go-pathlib/internal/testutils"
+	"github.com/spf13/afero"
+)
+
+func TestLines(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	file := tmpdir.Join("file.txt")
+	require.NoError(file.WriteFile([]byte("one\ntwo\r\n\nfour")))
+
+	scanner, err := file.Lines()
+	require.NoError(err)
+	defer scanner.Close()
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	assert.NoError(scanner.Err())
+	assert.Equal([]string{"one", "two", "", "four"}, lines)
+}
+
+func TestScannerMaxTokenSize(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	file := NewPathWithFS(afero.NewMemMapFs(), "/file.txt")
+	require.NoError(file.WriteFile([]byte("short\n" + strings.Repeat("x", 100) + "\n")))
+
+	scanner, err := file.Scanner(16)
+	require.NoError(err)
+	defer scanner.Close()
+	assert.True(scanner.Scan())
+	assert.Equal("short", scanner.Text())
+	assert.False(scanner.Scan())
+	assert.True(errors.Is(scanner.Err(), bufio.ErrTooLong))
+
+	for _, size := range []int{0, -1} {
+		_, err = file.Scanner(size)
+		var pathErr *PathError
+		require.True(errors.As(err, &pathErr))
+		assert.Equal("scan", pathErr.Op)
+	}
+}
+
+func TestLinesDoesNotExist(t *testing.T) {
+	assert, _, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	_, err := tmpdir.Join("i_dont_exist").Lines()
+	assert.True(errors.Is(err, fs.ErrNotExist))
+}
+
+func TestHead(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	file := tmpdir.Join("file.txt")
+	require.NoError(file.WriteFile([]byte("one\ntwo\nthree\n")))
+
+	lines, err := file.Head(2)
+	require.NoError(err)
+	assert.Equal([]string{"one", "two"}, lines)
+
+	lines, err = file.Head(10)
+	require.NoError(err)
+	assert.Equal([]string{"one", "two", "three"}, lines)
+
+	for _, n := range []int{0, -1} {
+		lines, err = file.Head(n)
+		require.NoError(err)
+		assert.Equal([]string{}, lines)
+	}
+}
+
+func TestTail(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	tests := []struct {
+		content  string
+		n        int
+		expected []string
+	}{
+		{"", 3, []string{}},
+		{"one\ntwo\nthree\n", 0, []string{}},
+		{"one\ntwo\nthree\n", -1, []string{}},
+		{"one\ntwo\nthree\n", 2, []string{"two", "three"}},
+		{"one\ntwo\nthree", 2, []string{"two", "three"}},
+		{"one\r\ntwo\r\nthree\r\n", 2, []string{"two", "three"}},
+		{"one\ntwo\nthree\n", 5, []string{"one", "two", "three"}},
+		{"one\n\n\n", 2, []string{"", ""}},
+	}
+	file := tmpdir.Join("file.txt")
+	for _, test := range tests {
+		require.NoError(file.WriteFile([]byte(test.content)))
+		lines, err := file.Tail(test.n)
+		require.NoError(err)
+		assert.Equal(test.expected, lines, "content %q, n %d", test.content, test.n)
+	}
+}
+
+func TestTailLargeFile(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	file := NewPathWithFS(afero.NewMemMapFs(), "/file.txt")
+	var sb strings.Builder
+	for i := 0; i < 5000; i++ {
+		fmt.Fprintf(&sb, "line %d\n", i)
+	}
+	require.NoError(file.WriteFile([]byte(sb.String())))
+
+	lines, err := file.Tail(3)
+	require.NoError(err)
+	assert.Equal([]string{"line 4997", "line 4998", "line 4999"}, lines)
+
+	lines, err = file.Tail(2000)
+	require.NoError(err)
+	assert.Equal(2000, len(lines))
+	assert.Equal("line 3000", lines[0])
+}
+
+func appendString(t *testing.T, p Path, s string) {
+	f, err := p.OpenFile(os.O_APPEND | os.O_WRONLY | os.O_CREATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(s); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFollow(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	file := tmpdir.Join("file.log")
+	require.NoError(file.WriteFile([]byte("old\n")))
+
+	lines := make(chan string, 100)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	opts := DefaultFollowOpts()
+	opts.PollInterval = 5 * time.Millisecond
+	go func() {
+		done <- file.FollowWithOpts(ctx, opts, func(line string) error {
+			lines <- line
+			return nil
+		})
+	}()
+
+	next := func() string {
+		select {
+		case line := <-lines:
+			return line
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for line")
+			return ""
+		}
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	appendString(t, file, "first\nsec")
+	assert.Equal("first", next())
+	appendString(t, file, "ond\n")
+	assert.Equal("second", next())
+
+	// truncation
+	require.NoError(file.WriteFile([]byte{}))
+	time.Sleep(20 * time.Millisecond)
+	appendString(t, file, "after truncate\n")
+	assert.Equal("after truncate", next())
+
+	// rotation
+	_, err := file.Rename(tmpdir.Join("file.log.1").String())
+	require.NoError(err)
+	time.Sleep(20 * time.Millisecond)
+	appendString(t, file, "after rotate\n")
+	assert.Equal("after rotate", next())
+
+	cancel()
+	assert.True(errors.Is(<-done, context.Canceled))
+}
+
+func TestFollowStopsOnError(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	file := NewPathWithFS(afero.NewMemMapFs(), "/file.log")
+	require.NoError(file.WriteFile([]byte("one\ntwo\n")))
+
+	// a zero PollInterval stands for the default one
+	opts := &FollowOpts{FromStart: true}
+	stop := errors.New("stop")
+	var lines []string
+	err := file.FollowWithOpts(context.Background(), opts, func(line string) error {
+		lines = append(lines, line)
+		if len(lines) == 2 {
+			return stop
+		}
+		return nil
+	})
+	assert.EqualError(stop, err)
+	assert.Equal([]string{"one", "two"}, lines)
+
+	assert.Error(file.FollowWithOpts(context.Background(), nil, func(line string) error {
+		return nil
+	}))
+}
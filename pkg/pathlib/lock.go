@@ -0,0 +1,325 @@
+package pathlib
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// UnlockFunc releases a lock acquired through one of the locking methods of
+// Path. Calling it more than once has no effect.
+type UnlockFunc func() error
+
+// Lock acquires an exclusive advisory lock on the file, blocking until it is
+// available. The file must exist; use LockFile to lock a dedicated lock file
+// that is created on demand.
+//
+// For OsFs the lock is an flock(2) lock, which coordinates all processes of the
+// system that use flock on the same file. Other afero filesystems (and
+// platforms without flock) fall back to an in-process lock table, which only
+// coordinates the goroutines of the current process. In both cases a lock
+// conflicts with any other lock acquired on the same file through a separate
+// call, even within the same goroutine.
+func (p Path) Lock() (UnlockFunc, error) {
+	return p.LockContext(context.Background())
+}
+
+// RLock acquires a shared advisory lock on the file, blocking until it is
+// available. Any number of shared locks may be held at the same time, but
+// none while an exclusive lock is held. See Lock for details.
+func (p Path) RLock() (UnlockFunc, error) {
+	l, err := p.acquireLock(context.Background(), false, true, false)
+	if err != nil {
+		return nil, err
+	}
+	return l.unlock, nil
+}
+
+// TryLock tries to acquire an exclusive advisory lock on the file without
+// blocking. It returns false if the lock is held by someone else. See Lock for
+// details.
+func (p Path) TryLock() (UnlockFunc, bool, error) {
+	l, err := p.acquireLock(context.Background(), true, false, false)
+	if err != nil || l == nil {
+		return nil, false, err
+	}
+	return l.unlock, true, nil
+}
+
+// LockContext acquires an exclusive advisory lock on the file, blocking until
+// it is available or the context is done. See Lock for details.
+//
+// OS file locks can't be waited for in a cancelable way, hence they are polled
+// every LockPollInterval.
+func (p Path) LockContext(ctx context.Context) (UnlockFunc, error) {
+	l, err := p.acquireLock(ctx, true, true, false)
+	if err != nil {
+		return nil, err
+	}
+	return l.unlock, nil
+}
+
+// LockFile uses the path as a dedicated lock file: it creates the file with
+// DefaultFileMode if it doesn't exist and acquires an exclusive lock on it,
+// blocking until it is available. Releasing the lock removes the file again.
+// See Lock for details.
+func (p Path) LockFile() (UnlockFunc, error) {
+	for {
+		l, err := p.acquireLock(context.Background(), true, true, true)
+		if err != nil {
+			return nil, err
+		}
+		if l.file != nil {
+			// the previous holder might have removed the file after we opened
+			// it; the lock is worthless then
+			locked, err := l.file.Stat()
+			if err != nil {
+				l.unlock()
+				return nil, p.pathError("stat", err)
+			}
+			current, err := p.Stat()
+			if errors.Is(err, fs.ErrNotExist) {
+				l.unlock()
+				continue
+			} else if err != nil {
+				l.unlock()
+				return nil, err
+			}
+			if same, known := sameFileInfo(locked, current); known && !same {
+				l.unlock()
+				continue
+			}
+		}
+		l.remove = true
+		return l.unlock, nil
+	}
+}
+
+// heldLock is a lock acquired by acquireLock.
+type heldLock struct {
+	path Path
+	once sync.Once
+
+	// file is the file the OS lock is held on, nil for in-process locks
+	file *File
+	// release releases an in-process lock
+	release func()
+	// remove specifies that the file is removed before the lock is released
+	remove bool
+}
+
+func (l *heldLock) unlock() (err error) {
+	l.once.Do(func() {
+		if l.remove {
+			if rerr := l.path.Remove(); rerr != nil && !errors.Is(rerr, fs.ErrNotExist) {
+				err = rerr
+			}
+		}
+		if l.file == nil {
+			l.release()
+			return
+		}
+		if uerr := funlock(l.file); err == nil {
+			err = l.path.pathError("unlock", uerr)
+		}
+		if cerr := l.file.Close(); err == nil {
+			err = l.path.pathError("close", cerr)
+		}
+	})
+	return err
+}
+
+// acquireLock acquires an exclusive or shared lock on the file. If wait is
+// false, it returns nil instead of blocking if the lock is held by someone
+// else. If create is true, the file is created if it doesn't exist.
+func (p Path) acquireLock(ctx context.Context, exclusive, wait, create bool) (*heldLock, error) {
+	flag := os.O_RDONLY
+	if create {
+		flag |= os.O_CREATE
+	}
+
+	if !p.flockable() {
+		release, ok, err := advisoryLocks.acquire(ctx, newLockKey(p), exclusive, wait)
+		if err != nil || !ok {
+			return nil, p.pathError("lock", err)
+		}
+		// the file is opened only to create it or make sure it exists
+		file, err := p.OpenFile(flag)
+		if err != nil {
+			release()
+			return nil, err
+		}
+		file.Close()
+		return &heldLock{path: p, release: release}, nil
+	}
+
+	file, err := p.OpenFile(flag)
+	if err != nil {
+		return nil, err
+	}
+	acquired := true
+	switch {
+	case !wait:
+		acquired, err = tryFlock(file, exclusive)
+	case ctx.Done() == nil:
+		_, err = flock(file, exclusive)
+	default:
+		acquired, err = pollFlock(ctx, file, exclusive)
+	}
+	if err != nil || !acquired {
+		file.Close()
+		return nil, p.pathError("lock", err)
+	}
+	return &heldLock{path: p, file: file}, nil
+}
+
+// pollFlock tries to acquire an OS lock every LockPollInterval until it
+// succeeds or the context is done.
+func pollFlock(ctx context.Context, file *File, exclusive bool) (bool, error) {
+	ticker := time.NewTicker(LockPollInterval)
+	defer ticker.Stop()
+	for {
+		acquired, err := tryFlock(file, exclusive)
+		if err != nil || acquired {
+			return acquired, err
+		}
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// flockable returns whether OS file locks are used for the path.
+func (p Path) flockable() bool {
+	return flockSupported && isOsFs(p.Fs())
+}
+
+// -----------------------------------------------------------------------------
+//
+// In-process lock table
+//
+// -----------------------------------------------------------------------------
+
+// lockKey identifies a file in the in-process lock table.
+type lockKey struct {
+	fs   any
+	path string
+}
+
+func newLockKey(p Path) lockKey {
+	return lockKey{fs: fsKey(p.Fs()), path: p.Clean().String()}
+}
+
+// osFsKey is the key of all OsFs values.
+type osFsKey struct{}
+
+// fsKey returns a value that identifies the filesystem and can be used as a
+// map key. Filesystems that can't be compared, as their dynamic type holds a
+// map or slice, are identified by their type. Their locks are shared more
+// widely than needed then, but still exclude each other.
+func fsKey(fs afero.Fs) any {
+	if isOsFs(fs) {
+		return osFsKey{}
+	}
+	if !reflect.ValueOf(fs).Comparable() {
+		return reflect.TypeOf(fs)
+	}
+	return fs
+}
+
+// lockEntry is a reference counted readers-writer lock of a lockTable.
+type lockEntry struct {
+	readers int
+	writer  bool
+	refs    int
+	// changed is closed and replaced whenever the lock is released
+	changed chan struct{}
+}
+
+// lockTable is a table of readers-writer locks keyed by file.
+type lockTable struct {
+	mu      sync.Mutex
+	entries map[lockKey]*lockEntry
+}
+
+var (
+	// advisoryLocks holds the advisory locks of filesystems that do not
+	// support OS file locks.
+	advisoryLocks = &lockTable{entries: map[lockKey]*lockEntry{}}
+	// writeLocks serializes writers of the current process.
+	writeLocks = &lockTable{entries: map[lockKey]*lockEntry{}}
+)
+
+// acquire acquires an exclusive or shared lock for the key. If wait is false,
+// it returns false instead of blocking if the lock is held by someone else.
+func (t *lockTable) acquire(ctx context.Context, key lockKey, exclusive, wait bool) (release func(), ok bool, err error) {
+	t.mu.Lock()
+	entry, found := t.entries[key]
+	if !found {
+		entry = &lockEntry{changed: make(chan struct{})}
+		t.entries[key] = entry
+	}
+	entry.refs++
+
+	for {
+		if exclusive && !entry.writer && entry.readers == 0 {
+			entry.writer = true
+			break
+		}
+		if !exclusive && !entry.writer {
+			entry.readers++
+			break
+		}
+		if !wait || ctx.Err() != nil {
+			t.unref(key, entry)
+			t.mu.Unlock()
+			return nil, false, ctx.Err()
+		}
+		changed := entry.changed
+		t.mu.Unlock()
+		select {
+		case <-changed:
+		case <-ctx.Done():
+		}
+		t.mu.Lock()
+	}
+	t.mu.Unlock()
+
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if exclusive {
+			entry.writer = false
+		} else {
+			entry.readers--
+		}
+		close(entry.changed)
+		entry.changed = make(chan struct{})
+		t.unref(key, entry)
+	}, true, nil
+}
+
+// unref drops a reference to the entry and removes it from the table once it
+// is unused. The caller must hold t.mu.
+func (t *lockTable) unref(key lockKey, entry *lockEntry) {
+	entry.refs--
+	if entry.refs == 0 {
+		delete(t.entries, key)
+	}
+}
+
+// lockInProcess acquires an exclusive in-process lock for the given path. It is
+// used for filesystems that do not support atomic appends, where it
+// serializes writes between goroutines of the current process.
+func lockInProcess(p Path) (unlock func()) {
+	release, _, _ := writeLocks.acquire(context.Background(), newLockKey(p), true, true)
+	return release
+}
@@ -0,0 +1,23 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package pathlib
+
+// flockSupported specifies whether flock(2) is available on this platform.
+const flockSupported = false
+
+// flock is not supported on this platform, the in-process lock table is used
+// instead.
+func flock(f *File, exclusive bool) (bool, error) {
+	return false, nil
+}
+
+// funlock is not supported on this platform.
+func funlock(f *File) error {
+	return nil
+}
+
+// tryFlock is not supported on this platform.
+func tryFlock(f *File, exclusive bool) (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,146 @@
+package pathlib
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aisbergg/go-pathlib/internal/testutils"
+	"github.com/spf13/afero"
+)
+
+func testLock(t *testing.T, file Path) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	require.NoError(file.WriteFile([]byte("hello")))
+
+	unlock, err := file.Lock()
+	require.NoError(err)
+	_, ok, err := file.TryLock()
+	require.NoError(err)
+	assert.False(ok, "acquired exclusive lock twice")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = file.LockContext(ctx)
+	assert.True(errors.Is(err, context.DeadlineExceeded))
+
+	require.NoError(unlock())
+	require.NoError(unlock())
+
+	// shared locks
+	runlock1, err := file.RLock()
+	require.NoError(err)
+	runlock2, err := file.RLock()
+	require.NoError(err)
+	_, ok, err = file.TryLock()
+	require.NoError(err)
+	assert.False(ok, "acquired exclusive lock while shared locks are held")
+	require.NoError(runlock1())
+	require.NoError(runlock2())
+
+	unlock, ok, err = file.TryLock()
+	require.NoError(err)
+	assert.True(ok)
+
+	// a waiting locker gets the lock once it is released
+	acquired := make(chan UnlockFunc)
+	go func() {
+		unlock, err := file.LockContext(context.Background())
+		if err != nil {
+			t.Error(err)
+		}
+		acquired <- unlock
+	}()
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(unlock())
+	select {
+	case unlock := <-acquired:
+		require.NoError(unlock())
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for lock")
+	}
+
+	_, err = file.Parent().Join("i_dont_exist").Lock()
+	assert.True(errors.Is(err, fs.ErrNotExist))
+}
+
+func TestLock(t *testing.T) {
+	_, _, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	testLock(t, tmpdir.Join("file.txt"))
+}
+
+func TestLockMemMapFs(t *testing.T) {
+	testLock(t, NewPathWithFS(afero.NewMemMapFs(), "/file.txt"))
+}
+
+func TestLockUncomparableFs(t *testing.T) {
+	testLock(t, NewPathWithFS(uncomparableFs{Fs: afero.NewMemMapFs()}, "/file.txt"))
+}
+
+func testLockFile(t *testing.T, lockFile Path) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+
+	var holders int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := lockFile.LockFile()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if n := atomic.AddInt32(&holders, 1); n != 1 {
+				t.Errorf("lock is held by %d lockers at the same time", n)
+			}
+			exists, err := lockFile.Exists()
+			if err != nil || !exists {
+				t.Error("lock file does not exist while being held")
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&holders, -1)
+			if err := unlock(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	exists, err := lockFile.Exists()
+	require.NoError(err)
+	assert.False(exists, "lock file was not removed")
+}
+
+func TestLockFile(t *testing.T) {
+	_, _, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	testLockFile(t, tmpdir.Join("file.lock"))
+}
+
+func TestLockFileMemMapFs(t *testing.T) {
+	testLockFile(t, NewPathWithFS(afero.NewMemMapFs(), "/file.lock"))
+}
+
+func TestLockDoesNotBlockAppend(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	file := NewPathWithFS(afero.NewMemMapFs(), "/file.txt")
+	require.NoError(file.WriteFile([]byte{}))
+
+	unlock, err := file.Lock()
+	require.NoError(err)
+	require.NoError(file.AppendText("hello"))
+	require.NoError(unlock())
+
+	content, err := file.ReadFile()
+	require.NoError(err)
+	assert.Equal("hello", string(content))
+}
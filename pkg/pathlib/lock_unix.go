@@ -0,0 +1,66 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package pathlib
+
+import (
+	"os"
+	"syscall"
+)
+
+// flockSupported specifies whether flock(2) is available on this platform.
+const flockSupported = true
+
+// flock acquires an advisory flock(2) on the given file. The returned bool is
+// false if the file is not backed by an OS file descriptor.
+func flock(f *File, exclusive bool) (bool, error) {
+	osFile, ok := f.File.(*os.File)
+	if !ok {
+		return false, nil
+	}
+	how := syscall.LOCK_SH
+	if exclusive {
+		how = syscall.LOCK_EX
+	}
+	for {
+		err := syscall.Flock(int(osFile.Fd()), how)
+		if err != syscall.EINTR {
+			return true, err
+		}
+	}
+}
+
+// funlock releases an advisory lock acquired with flock.
+func funlock(f *File) error {
+	osFile, ok := f.File.(*os.File)
+	if !ok {
+		return nil
+	}
+	return syscall.Flock(int(osFile.Fd()), syscall.LOCK_UN)
+}
+
+// tryFlock tries to acquire an advisory flock(2) on the given file without
+// blocking. It returns false if the lock is held by someone else.
+func tryFlock(f *File, exclusive bool) (bool, error) {
+	osFile, ok := f.File.(*os.File)
+	if !ok {
+		return false, nil
+	}
+	how := syscall.LOCK_SH | syscall.LOCK_NB
+	if exclusive {
+		how = syscall.LOCK_EX | syscall.LOCK_NB
+	}
+	for {
+		err := syscall.Flock(int(osFile.Fd()), how)
+		switch err {
+		case nil:
+			return true, nil
+		case syscall.EWOULDBLOCK:
+			return false, nil
+		case syscall.EINTR:
+			continue
+		default:
+			return false, err
+		}
+	}
+}
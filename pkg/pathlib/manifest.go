@@ -0,0 +1,260 @@
+package pathlib
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ManifestFormat is the line format of a checksum manifest.
+type ManifestFormat int
+
+const (
+	// ManifestGNU is the format used by the GNU coreutils (`sha256sum` etc.):
+	// "<digest>  <path>".
+	ManifestGNU ManifestFormat = iota
+	// ManifestBSD is the format used by BSD tools and `sha256sum --tag`:
+	// "SHA256 (<path>) = <digest>".
+	ManifestBSD
+)
+
+// ManifestResult is the result of a manifest verification. All paths are
+// relative to the verified root and use forward slashes.
+type ManifestResult struct {
+	// Missing lists files that are listed in the manifest but do not exist.
+	Missing []string
+	// Extra lists files that exist but are not listed in the manifest.
+	Extra []string
+	// Modified lists files whose digest does not match the manifest.
+	Modified []string
+	// Matched lists files whose digest matches the manifest.
+	Matched []string
+}
+
+// Valid returns whether the verified tree matches the manifest exactly.
+func (r *ManifestResult) Valid() bool {
+	return len(r.Missing) == 0 && len(r.Extra) == 0 && len(r.Modified) == 0
+}
+
+// manifestEntry is a single line of a manifest.
+type manifestEntry struct {
+	alg    HashAlgorithm
+	path   string
+	digest string
+}
+
+// WriteManifest writes a checksum manifest of all regular files in the tree
+// rooted at root to the path. The format defaults to ManifestGNU, which can be
+// checked with `sha256sum -c` (or the tool matching alg) from inside root.
+// Symlinks are not followed and the manifest itself is not listed.
+func (p Path) WriteManifest(root Path, alg HashAlgorithm, format ...ManifestFormat) error {
+	f := ManifestGNU
+	if len(format) > 0 {
+		f = format[0]
+	}
+	if _, err := NewHash(alg); err != nil {
+		return p.pathError("manifest", err)
+	}
+	files, err := p.manifestFiles(root)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	for _, rel := range files {
+		digest, err := root.Join(rel).Hash(alg)
+		if err != nil {
+			return err
+		}
+		entry := manifestEntry{alg: alg, path: rel, digest: hex.EncodeToString(digest)}
+		buf.WriteString(entry.format(f))
+		buf.WriteByte('\n')
+	}
+	return p.WriteFile(buf.Bytes())
+}
+
+// VerifyManifest checks the tree rooted at root against the manifest at the
+// path. Both GNU and BSD style lines are understood. For GNU style lines the
+// algorithm is taken from alg or, if not given, derived from the digest
+// length. A returned error indicates that the verification could not be
+// performed; mismatches are reported through the ManifestResult.
+func (p Path) VerifyManifest(root Path, alg ...HashAlgorithm) (*ManifestResult, error) {
+	var defaultAlg HashAlgorithm
+	if len(alg) > 0 {
+		defaultAlg = alg[0]
+	}
+	entries, err := p.readManifest(defaultAlg)
+	if err != nil {
+		return nil, err
+	}
+	files, err := p.manifestFiles(root)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ManifestResult{}
+	existing := make(map[string]bool, len(files))
+	for _, rel := range files {
+		existing[rel] = true
+	}
+	listed := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		listed[entry.path] = true
+		if !existing[entry.path] {
+			result.Missing = append(result.Missing, entry.path)
+			continue
+		}
+		digest, err := root.Join(entry.path).Hash(entry.alg)
+		if err != nil {
+			return nil, err
+		}
+		if hex.EncodeToString(digest) == strings.ToLower(entry.digest) {
+			result.Matched = append(result.Matched, entry.path)
+		} else {
+			result.Modified = append(result.Modified, entry.path)
+		}
+	}
+	for _, rel := range files {
+		if !listed[rel] {
+			result.Extra = append(result.Extra, rel)
+		}
+	}
+	sort.Strings(result.Missing)
+	sort.Strings(result.Modified)
+	sort.Strings(result.Matched)
+	return result, nil
+}
+
+// manifestFiles returns the sorted, slash separated paths of all regular
+// files below root, excluding the manifest p itself.
+func (p Path) manifestFiles(root Path) ([]string, error) {
+	walk, err := NewWalk(root)
+	if err != nil {
+		return nil, err
+	}
+	walk.Opts.VisitDirs = false
+	walk.Opts.VisitSymlinks = false
+
+	var files []string
+	err = walk.Walk(func(path Path, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !IsFile(info.Mode()) || path.Clean().Equals(p.Clean()) {
+			return nil
+		}
+		rel, err := path.RelativeToPath(root)
+		if err != nil {
+			return err
+		}
+		files = append(files, rel.AsPosix())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// readManifest reads and parses the manifest at the path.
+func (p Path) readManifest(defaultAlg HashAlgorithm) ([]manifestEntry, error) {
+	scanner, err := p.Lines()
+	if err != nil {
+		return nil, err
+	}
+	defer scanner.Close()
+
+	var entries []manifestEntry
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entry, err := parseManifestLine(line, defaultAlg)
+		if err != nil {
+			return nil, p.pathError("manifest", fmt.Errorf("line %d: %w", lineNo, err))
+		}
+		entries = append(entries, entry)
+	}
+	return entries, p.pathError("read", scanner.Err())
+}
+
+// format returns the manifest line of the entry in the given format.
+func (e manifestEntry) format(f ManifestFormat) string {
+	if f == ManifestBSD {
+		return fmt.Sprintf("%s (%s) = %s", strings.ToUpper(string(e.alg)), e.path, e.digest)
+	}
+	// GNU tools mark lines with escaped file names with a leading backslash
+	if strings.ContainsAny(e.path, "\\\n") {
+		return fmt.Sprintf("\\%s  %s", e.digest, escapeManifestPath(e.path))
+	}
+	return fmt.Sprintf("%s  %s", e.digest, e.path)
+}
+
+// parseManifestLine parses a GNU or BSD style manifest line.
+func parseManifestLine(line string, defaultAlg HashAlgorithm) (manifestEntry, error) {
+	// BSD style: "ALG (path) = digest"; a GNU style line may contain the same
+	// markers as part of the path, but never starts with a known algorithm
+	if i := strings.Index(line, " ("); i > 0 && !strings.ContainsAny(line[:i], " \\") {
+		alg := HashAlgorithm(strings.ToLower(line[:i]))
+		if j := strings.LastIndex(line, ") = "); j > i && isHashAlgorithm(alg) {
+			return manifestEntry{
+				alg:    alg,
+				path:   line[i+2 : j],
+				digest: line[j+4:],
+			}, nil
+		}
+	}
+
+	// GNU style: "digest  path" or "digest *path"
+	escaped := strings.HasPrefix(line, "\\")
+	if escaped {
+		line = line[1:]
+	}
+	i := strings.IndexByte(line, ' ')
+	if i <= 0 || i+2 > len(line) || (line[i+1] != ' ' && line[i+1] != '*') {
+		return manifestEntry{}, fmt.Errorf("%w: malformed manifest line", ErrInvalidManifest)
+	}
+	entry := manifestEntry{
+		alg:    defaultAlg,
+		digest: line[:i],
+		path:   line[i+2:],
+	}
+	if escaped {
+		entry.path = unescapeManifestPath(entry.path)
+	}
+	if entry.alg == "" {
+		switch len(entry.digest) {
+		case 8:
+			entry.alg = HashCRC32
+		case 32:
+			entry.alg = HashMD5
+		case 40:
+			entry.alg = HashSHA1
+		case 64:
+			entry.alg = HashSHA256
+		default:
+			return manifestEntry{}, fmt.Errorf("%w: cannot derive algorithm from digest length", ErrInvalidManifest)
+		}
+	}
+	return entry, nil
+}
+
+var (
+	manifestEscaper   = strings.NewReplacer("\\", "\\\\", "\n", "\\n")
+	manifestUnescaper = strings.NewReplacer("\\\\", "\\", "\\n", "\n")
+)
+
+func escapeManifestPath(path string) string {
+	return manifestEscaper.Replace(path)
+}
+
+func unescapeManifestPath(path string) string {
+	return manifestUnescaper.Replace(path)
+}
@@ -0,0 +1,145 @@
+package pathlib
+
+import (
+	"encoding/hex"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/aisbergg/go-pathlib/internal/testutils"
+	"github.com/spf13/afero"
+)
+
+func TestWriteManifest(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	root := NewPathWithFS(afero.NewMemMapFs(), "/root")
+	require.NoError(root.MkdirAll())
+	require.NoError(TwoFilesAtRootTwoInSubdir(root))
+	manifest := root.Join("SHA256SUMS")
+
+	require.NoError(manifest.WriteManifest(root, HashSHA256))
+	content, err := manifest.ReadFile()
+	require.NoError(err)
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	require.Equal(4, len(lines))
+	digest, err := root.Join("file0.txt").Hash(HashSHA256)
+	require.NoError(err)
+	assert.Equal(hex.EncodeToString(digest)+"  file0.txt", lines[0])
+	assert.True(strings.HasSuffix(lines[3], "  subdir/file1.txt"))
+
+	bsd := root.Join("MD5SUMS")
+	require.NoError(bsd.WriteManifest(root, HashMD5, ManifestBSD))
+	content, err = bsd.ReadFile()
+	require.NoError(err)
+	lines = strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	// the GNU manifest written before is part of the tree now
+	require.Equal(5, len(lines))
+	assert.True(strings.HasPrefix(lines[0], "MD5 (SHA256SUMS) = "))
+	assert.True(strings.HasPrefix(lines[1], "MD5 (file0.txt) = "))
+}
+
+func TestVerifyManifest(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	for _, format := range []ManifestFormat{ManifestGNU, ManifestBSD} {
+		fs := afero.NewMemMapFs()
+		root := NewPathWithFS(fs, "/root")
+		require.NoError(root.MkdirAll())
+		require.NoError(TwoFilesAtRootTwoInSubdir(root))
+		manifest := NewPathWithFS(fs, "/SUMS")
+		require.NoError(manifest.WriteManifest(root, HashSHA1, format))
+
+		result, err := manifest.VerifyManifest(root)
+		require.NoError(err)
+		assert.True(result.Valid())
+		assert.Equal(4, len(result.Matched))
+
+		require.NoError(root.Join("file0.txt").Remove())
+		require.NoError(OneFile(root, "extra.txt", "extra"))
+		require.NoError(OneFile(root.Join("subdir"), "file1.txt", "modified"))
+		result, err = manifest.VerifyManifest(root)
+		require.NoError(err)
+		assert.False(result.Valid())
+		assert.Equal([]string{"file0.txt"}, result.Missing)
+		assert.Equal([]string{"extra.txt"}, result.Extra)
+		assert.Equal([]string{"subdir/file1.txt"}, result.Modified)
+		assert.Equal([]string{"file1.txt", "subdir/file0.txt"}, result.Matched)
+	}
+}
+
+func TestVerifyManifestInvalid(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	fs := afero.NewMemMapFs()
+	root := NewPathWithFS(fs, "/root")
+	require.NoError(root.MkdirAll())
+	manifest := NewPathWithFS(fs, "/SUMS")
+
+	require.NoError(manifest.WriteFile([]byte("not a manifest\n")))
+	_, err := manifest.VerifyManifest(root)
+	assert.EqualError(ErrInvalidManifest, err)
+
+	require.NoError(manifest.WriteFile([]byte("abc  file.txt\n")))
+	_, err = manifest.VerifyManifest(root)
+	assert.EqualError(ErrInvalidManifest, err)
+}
+
+func TestManifestEscaping(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	fs := afero.NewMemMapFs()
+	root := NewPathWithFS(fs, "/root")
+	require.NoError(root.MkdirAll())
+	require.NoError(OneFile(root, "new\nline.txt", "content"))
+	manifest := NewPathWithFS(fs, "/SUMS")
+	require.NoError(manifest.WriteManifest(root, HashSHA256))
+
+	content, err := manifest.ReadFile()
+	require.NoError(err)
+	assert.True(strings.HasPrefix(string(content), "\\"))
+	assert.True(strings.HasSuffix(string(content), "  new\\nline.txt\n"))
+
+	result, err := manifest.VerifyManifest(root)
+	require.NoError(err)
+	assert.True(result.Valid())
+}
+
+func TestManifestBSDLookalike(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	for _, format := range []ManifestFormat{ManifestGNU, ManifestBSD} {
+		fs := afero.NewMemMapFs()
+		root := NewPathWithFS(fs, "/root")
+		require.NoError(root.MkdirAll())
+		require.NoError(OneFile(root, "foo (1) = x", "content"))
+		manifest := NewPathWithFS(fs, "/SUMS")
+		require.NoError(manifest.WriteManifest(root, HashSHA256, format))
+
+		result, err := manifest.VerifyManifest(root)
+		require.NoError(err)
+		assert.True(result.Valid())
+		assert.Equal([]string{"foo (1) = x"}, result.Matched)
+	}
+
+	// an unknown algorithm token does not make a BSD style line
+	entry, err := parseManifestLine("0123abcd  foo (1) = x", "")
+	require.NoError(err)
+	assert.Equal(manifestEntry{alg: HashCRC32, path: "foo (1) = x", digest: "0123abcd"}, entry)
+}
+
+func TestManifestSha256sumCompatible(t *testing.T) {
+	if _, err := exec.LookPath("sha256sum"); err != nil {
+		t.Skip("sha256sum not available")
+	}
+	_, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	require.NoError(TwoFilesAtRootTwoInSubdir(tmpdir))
+	manifest := tmpdir.Join("SHA256SUMS")
+	require.NoError(manifest.WriteManifest(tmpdir, HashSHA256))
+
+	cmd := exec.Command("sha256sum", "--strict", "-c", "SHA256SUMS")
+	cmd.Dir = tmpdir.String()
+	out, err := cmd.CombinedOutput()
+	require.NoError(err, "sha256sum failed: %s", out)
+}
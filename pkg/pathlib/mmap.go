@@ -0,0 +1,83 @@
+package pathlib
+
+import (
+	"errors"
+	"io"
+	"os"
+)
+
+// MappedFile is a read-only view of the content of a file, created by Mmap.
+// It must be closed to release the mapping.
+type MappedFile struct {
+	data   []byte
+	closed bool
+	// unmap releases the memory mapping, nil if the content was read into
+	// memory
+	unmap func([]byte) error
+}
+
+// Bytes returns the content of the file. The slice must not be modified and
+// must not be used after the MappedFile is closed.
+func (m *MappedFile) Bytes() []byte {
+	return m.data
+}
+
+// Len returns the size of the content.
+func (m *MappedFile) Len() int {
+	return len(m.data)
+}
+
+// ReadAt implements io.ReaderAt.
+func (m *MappedFile) ReadAt(b []byte, off int64) (int, error) {
+	if m.closed {
+		return 0, os.ErrClosed
+	}
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	if off >= int64(len(m.data)) {
+		return 0, io.EOF
+	}
+	n := copy(b, m.data[off:])
+	if n < len(b) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Close releases the mapping. Calling Close more than once has no effect.
+func (m *MappedFile) Close() error {
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	data := m.data
+	m.data = nil
+	if m.unmap == nil || len(data) == 0 {
+		return nil
+	}
+	return m.unmap(data)
+}
+
+// Mmap maps the file into memory for reading. For OsFs the file is mapped with
+// mmap(2), so the content is paged in on demand and shared with the page cache
+// instead of being copied. For other afero filesystems and on platforms
+// without mmap, the content is read into memory instead.
+//
+// Modifying the file while it is mapped changes the content of the mapping;
+// truncating it causes accesses beyond the new end to fault.
+func (p Path) Mmap() (*MappedFile, error) {
+	if !isOsFs(p.Fs()) {
+		return p.readMapped()
+	}
+	return p.mmap()
+}
+
+// readMapped reads the file into memory.
+func (p Path) readMapped() (*MappedFile, error) {
+	data, err := p.ReadFile()
+	if err != nil {
+		return nil, err
+	}
+	return &MappedFile{data: data}, nil
+}
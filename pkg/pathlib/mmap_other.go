@@ -0,0 +1,10 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package pathlib
+
+// mmap is not supported on this platform, the file is read into memory
+// instead.
+func (p Path) mmap() (*MappedFile, error) {
+	return p.readMapped()
+}
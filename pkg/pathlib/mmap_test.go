@@ -0,0 +1,76 @@
+package pathlib
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/aisbergg/go-pathlib/internal/testutils"
+	"github.com/spf13/afero"
+)
+
+func testMmap(t *testing.T, root Path) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	file := root.Join("file.txt")
+	content := strings.Repeat("0123456789", 1000)
+	require.NoError(file.WriteFile([]byte(content)))
+
+	mapped, err := file.Mmap()
+	require.NoError(err)
+	assert.Equal(len(content), mapped.Len())
+	assert.Equal(content, string(mapped.Bytes()))
+
+	buf := make([]byte, 4)
+	n, err := mapped.ReadAt(buf, 12)
+	require.NoError(err)
+	assert.Equal(4, n)
+	assert.Equal("2345", string(buf))
+	n, err = mapped.ReadAt(buf, int64(len(content)-2))
+	assert.Equal(io.EOF, err)
+	assert.Equal(2, n)
+	assert.Equal("89", string(buf[:n]))
+	_, err = mapped.ReadAt(buf, int64(len(content)))
+	assert.Equal(io.EOF, err)
+
+	// the reader can be used with the io package
+	section, err := io.ReadAll(io.NewSectionReader(mapped, 5, 10))
+	require.NoError(err)
+	assert.Equal("5678901234", string(section))
+
+	require.NoError(mapped.Close())
+	require.NoError(mapped.Close())
+	_, err = mapped.ReadAt(buf, 0)
+	assert.EqualError(os.ErrClosed, err)
+
+	// empty files
+	empty := root.Join("empty.txt")
+	require.NoError(empty.WriteFile([]byte{}))
+	mapped, err = empty.Mmap()
+	require.NoError(err)
+	assert.Equal(0, mapped.Len())
+	_, err = mapped.ReadAt(buf, 0)
+	assert.Equal(io.EOF, err)
+	require.NoError(mapped.Close())
+
+	_, err = root.Join("i_dont_exist").Mmap()
+	assert.True(errors.Is(err, fs.ErrNotExist))
+}
+
+func TestMmap(t *testing.T) {
+	_, _, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	testMmap(t, tmpdir)
+
+	_, err := tmpdir.Mmap()
+	testutils.NewAssert(t).Error(err)
+}
+
+func TestMmapMemMapFs(t *testing.T) {
+	root := NewPathWithFS(afero.NewMemMapFs(), "/root")
+	testutils.NewRequire(t).NoError(root.MkdirAll())
+	testMmap(t, root)
+}
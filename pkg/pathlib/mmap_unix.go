@@ -0,0 +1,42 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// mmap maps the file with mmap(2).
+func (p Path) mmap() (*MappedFile, error) {
+	file, err := os.Open(p.String())
+	if err != nil {
+		return nil, p.pathError("open", err)
+	}
+	// the mapping stays valid after the file is closed
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, p.pathError("stat", err)
+	}
+	if !info.Mode().IsRegular() {
+		return nil, p.pathError("mmap", syscall.ENODEV)
+	}
+	size := info.Size()
+	if size == 0 {
+		// empty mappings are not possible
+		return &MappedFile{}, nil
+	}
+	if size != int64(int(size)) {
+		return nil, p.pathError("mmap", fmt.Errorf("file too large: %d bytes", size))
+	}
+
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, p.pathError("mmap", err)
+	}
+	return &MappedFile{data: data, unmap: syscall.Munmap}, nil
+}
@@ -0,0 +1,160 @@
+package pathlib
+
+import (
+	"os"
+	"os/user"
+	"strconv"
+)
+
+// Chowner is implemented by afero filesystems that support changing the
+// ownership of files.
+type Chowner interface {
+	Chown(name string, uid, gid int) error
+}
+
+// Lchowner is implemented by afero filesystems that support changing the
+// ownership of symlinks themselves.
+type Lchowner interface {
+	Lchown(name string, uid, gid int) error
+}
+
+// Chown changes the numeric uid and gid of the file. A uid or gid of -1 means
+// to not change that value. Symlinks are followed.
+//
+// This will fail if the underlying afero filesystem is neither OsFs nor
+// implements Chowner.
+func (p Path) Chown(uid, gid int) error {
+	if isOsFs(p.Fs()) {
+		return p.pathError("chown", os.Chown(p.String(), uid, gid))
+	}
+	chowner, ok := p.Fs().(Chowner)
+	if !ok {
+		return p.pathError("chown", p.doesNotImplementErr("pathlib.Chowner"))
+	}
+	return p.pathError("chown", chowner.Chown(p.String(), uid, gid))
+}
+
+// Lchown changes the numeric uid and gid of the file. If the file is a
+// symlink, the symlink itself is changed.
+//
+// This will fail if the underlying afero filesystem is neither OsFs nor
+// implements Lchowner.
+func (p Path) Lchown(uid, gid int) error {
+	if isOsFs(p.Fs()) {
+		return p.pathError("lchown", os.Lchown(p.String(), uid, gid))
+	}
+	lchowner, ok := p.Fs().(Lchowner)
+	if !ok {
+		return p.pathError("lchown", p.doesNotImplementErr("pathlib.Lchowner"))
+	}
+	return p.pathError("lchown", lchowner.Lchown(p.String(), uid, gid))
+}
+
+// ChownByName changes the owner and group of the file to the given user and
+// group names. An empty name means to not change that value.
+func (p Path) ChownByName(owner, group string) error {
+	uid, gid, err := lookupIDs(owner, group)
+	if err != nil {
+		return p.pathError("chown", err)
+	}
+	return p.Chown(uid, gid)
+}
+
+// ChownAll changes the numeric uid and gid of the path and, if it is a
+// directory, of everything below it. Symlinks are not followed; the symlinks
+// themselves are changed instead. A uid or gid of -1 means to not change that
+// value.
+func (p Path) ChownAll(uid, gid int) error {
+	info, err := p.lstatIfPossible()
+	if err != nil {
+		return err
+	}
+	if err := chownInfo(p, info, uid, gid); err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return nil
+	}
+
+	walk, err := NewWalk(p)
+	if err != nil {
+		return err
+	}
+	return walk.Walk(func(path Path, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		return chownInfo(path, info, uid, gid)
+	})
+}
+
+// chownInfo changes the ownership of the path, using Lchown for symlinks.
+func chownInfo(p Path, info os.FileInfo, uid, gid int) error {
+	if IsSymlink(info.Mode()) {
+		return p.Lchown(uid, gid)
+	}
+	return p.Chown(uid, gid)
+}
+
+// Owner returns the name of the user owning the file.
+//
+// This will fail with an ErrDoesNotImplement if the underlying afero
+// filesystem does not provide ownership information.
+func (p Path) Owner() (string, error) {
+	stat, err := p.StatEx()
+	if err != nil {
+		return "", err
+	}
+	if !stat.Has(StatUID) {
+		return "", p.pathError("owner", p.doesNotImplementErr("ownership information"))
+	}
+	u, err := user.LookupId(strconv.FormatUint(uint64(stat.UID), 10))
+	if err != nil {
+		return "", p.pathError("owner", err)
+	}
+	return u.Username, nil
+}
+
+// Group returns the name of the group owning the file.
+//
+// This will fail with an ErrDoesNotImplement if the underlying afero
+// filesystem does not provide ownership information.
+func (p Path) Group() (string, error) {
+	stat, err := p.StatEx()
+	if err != nil {
+		return "", err
+	}
+	if !stat.Has(StatGID) {
+		return "", p.pathError("group", p.doesNotImplementErr("ownership information"))
+	}
+	g, err := user.LookupGroupId(strconv.FormatUint(uint64(stat.GID), 10))
+	if err != nil {
+		return "", p.pathError("group", err)
+	}
+	return g.Name, nil
+}
+
+// lookupIDs returns the numeric uid and gid of the given user and group names.
+// Empty names result in -1.
+func lookupIDs(owner, group string) (uid, gid int, err error) {
+	uid, gid = -1, -1
+	if owner != "" {
+		u, err := user.Lookup(owner)
+		if err != nil {
+			return -1, -1, err
+		}
+		if uid, err = strconv.Atoi(u.Uid); err != nil {
+			return -1, -1, err
+		}
+	}
+	if group != "" {
+		g, err := user.LookupGroup(group)
+		if err != nil {
+			return -1, -1, err
+		}
+		if gid, err = strconv.Atoi(g.Gid); err != nil {
+			return -1, -1, err
+		}
+	}
+	return uid, gid, nil
+}
@@ -0,0 +1,108 @@
+package pathlib
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"os/user"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/aisbergg/go-pathlib/internal/testutils"
+	"github.com/spf13/afero"
+)
+
+func TestChown(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("ownership is not supported on Windows")
+	}
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	require.NoError(TwoFilesAtRootTwoInSubdir(tmpdir))
+	require.NoError(tmpdir.Join("symlink").Symlink(NewPath("file0.txt")))
+	uid, gid := os.Getuid(), os.Getgid()
+
+	file := tmpdir.Join("file0.txt")
+	assert.NoError(file.Chown(uid, gid))
+	assert.NoError(file.Chown(-1, -1))
+	assert.NoError(tmpdir.Join("symlink").Lchown(uid, gid))
+	assert.NoError(tmpdir.ChownAll(uid, gid))
+	assert.True(errors.Is(tmpdir.Join("i_dont_exist").Chown(uid, gid), fs.ErrNotExist))
+
+	stat, err := file.StatEx()
+	require.NoError(err)
+	assert.Equal(uint32(uid), stat.UID)
+	assert.Equal(uint32(gid), stat.GID)
+}
+
+func TestOwnerAndGroup(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("ownership is not supported on Windows")
+	}
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	file := tmpdir.Join("file.txt")
+	require.NoError(file.WriteFile([]byte("hello")))
+
+	u, err := user.LookupId(strconv.Itoa(os.Getuid()))
+	if err != nil {
+		t.Skipf("current user can't be looked up: %v", err)
+	}
+	g, err := user.LookupGroupId(strconv.Itoa(os.Getgid()))
+	if err != nil {
+		t.Skipf("current group can't be looked up: %v", err)
+	}
+
+	owner, err := file.Owner()
+	require.NoError(err)
+	assert.Equal(u.Username, owner)
+	group, err := file.Group()
+	require.NoError(err)
+	assert.Equal(g.Name, group)
+
+	assert.NoError(file.ChownByName(u.Username, g.Name))
+	assert.NoError(file.ChownByName("", ""))
+	assert.Error(file.ChownByName("pathlib-no-such-user", ""))
+}
+
+func TestChownMemMapFs(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	root := NewPathWithFS(afero.NewMemMapFs(), "/root")
+	require.NoError(root.MkdirAll())
+	require.NoError(TwoFilesAtRootTwoInSubdir(root))
+	file := root.Join("file0.txt")
+
+	assert.True(errors.Is(file.Chown(0, 0), ErrDoesNotImplement))
+	assert.True(errors.Is(file.Lchown(0, 0), ErrDoesNotImplement))
+	assert.True(errors.Is(root.ChownAll(0, 0), ErrDoesNotImplement))
+	_, err := file.Owner()
+	assert.True(errors.Is(err, ErrDoesNotImplement))
+	_, err = file.Group()
+	assert.True(errors.Is(err, ErrDoesNotImplement))
+}
+
+// chownFs is a MemMapFs that records Chown calls.
+type chownFs struct {
+	*afero.MemMapFs
+	chowned map[string][2]int
+}
+
+func (fs *chownFs) Chown(name string, uid, gid int) error {
+	fs.chowned[name] = [2]int{uid, gid}
+	return nil
+}
+
+func TestChownAllChowner(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	fs := &chownFs{MemMapFs: &afero.MemMapFs{}, chowned: map[string][2]int{}}
+	root := NewPathWithFS(fs, "/root")
+	require.NoError(root.MkdirAll())
+	require.NoError(TwoFilesAtRootTwoInSubdir(root))
+
+	require.NoError(root.ChownAll(1000, -1))
+	assert.Equal(6, len(fs.chowned))
+	assert.Equal([2]int{1000, -1}, fs.chowned["/root/subdir/file1.txt"])
+}
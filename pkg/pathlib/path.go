@@ -1,8 +1,10 @@
 package pathlib
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -148,7 +150,7 @@ func lstatNotPossible(fs afero.Fs) error {
 // Create creates a file if possible, returning the file and an error, if any happens.
 func (p Path) Create() (File, error) {
 	file, err := p.Fs().Create(p.String())
-	return File{file}, err
+	return File{file}, p.pathError("create", err)
 }
 
 // Mkdir makes the current dir. If the parents don't exist, an error
@@ -158,7 +160,7 @@ func (p Path) Mkdir(perm ...os.FileMode) error {
 	if len(perm) > 0 {
 		mode = perm[0]
 	}
-	return p.Fs().Mkdir(p.String(), mode)
+	return p.pathError("mkdir", p.Fs().Mkdir(p.String(), mode))
 }
 
 // MkdirAll makes all of the directories up to, and including, the given path.
@@ -167,7 +169,7 @@ func (p Path) MkdirAll(perm ...os.FileMode) error {
 	if len(perm) > 0 {
 		mode = perm[0]
 	}
-	return p.Fs().MkdirAll(p.String(), mode)
+	return p.pathError("mkdirall", p.Fs().MkdirAll(p.String(), mode))
 }
 
 // Open opens a file for read-only, returning it or an error, if any happens.
@@ -175,7 +177,7 @@ func (p Path) Open() (*File, error) {
 	handle, err := p.Fs().Open(p.String())
 	return &File{
 		File: handle,
-	}, err
+	}, p.pathError("open", err)
 }
 
 // OpenFile opens a file using the given flags and (optionally) given mode.
@@ -188,25 +190,25 @@ func (p Path) OpenFile(flag int, perm ...os.FileMode) (*File, error) {
 	handle, err := p.Fs().OpenFile(p.String(), flag, mode)
 	return &File{
 		File: handle,
-	}, err
+	}, p.pathError("open", err)
 }
 
 // Remove removes a file, returning an error, if any
 // happens.
 func (p Path) Remove() error {
-	return p.Fs().Remove(p.String())
+	return p.pathError("remove", p.Fs().Remove(p.String()))
 }
 
 // RemoveAll removes the given path and all of its children.
 func (p Path) RemoveAll() error {
-	return p.Fs().RemoveAll(p.String())
+	return p.pathError("removeall", p.Fs().RemoveAll(p.String()))
 }
 
 // Rename renames the path to the given target path.
 func (p Path) Rename(target string) (Path, error) {
 	newPath := copyPathWithPaths(p, target)
 	if err := p.Fs().Rename(p.String(), newPath.String()); err != nil {
-		return Path{}, err
+		return Path{}, p.pathError("rename", err)
 	}
 	return newPath, nil
 }
@@ -218,17 +220,18 @@ func (p Path) RenamePath(target Path) (Path, error) {
 
 // Stat returns the os.FileInfo of the path.
 func (p Path) Stat() (os.FileInfo, error) {
-	return p.Fs().Stat(p.String())
+	info, err := p.Fs().Stat(p.String())
+	return info, p.pathError("stat", err)
 }
 
 // Chmod changes the file mode of the given path
 func (p Path) Chmod(mode os.FileMode) error {
-	return p.Fs().Chmod(p.String(), mode)
+	return p.pathError("chmod", p.Fs().Chmod(p.String(), mode))
 }
 
 // Chtimes changes the modification and access time of the given path.
 func (p Path) Chtimes(atime time.Time, mtime time.Time) error {
-	return p.Fs().Chtimes(p.String(), atime, mtime)
+	return p.pathError("chtimes", p.Fs().Chtimes(p.String(), atime, mtime))
 }
 
 // -----------------------------------------------------------------------------
@@ -239,28 +242,33 @@ func (p Path) Chtimes(atime time.Time, mtime time.Time) error {
 
 // DirExists returns whether or not the path represents a directory that exists
 func (p Path) DirExists() (bool, error) {
-	return afero.DirExists(p.Fs(), p.String())
+	exists, err := afero.DirExists(p.Fs(), p.String())
+	return exists, p.pathError("direxists", err)
 }
 
 // Exists returns whether the path exists
 func (p Path) Exists() (bool, error) {
-	return afero.Exists(p.Fs(), p.String())
+	exists, err := afero.Exists(p.Fs(), p.String())
+	return exists, p.pathError("exists", err)
 }
 
 // FileContainsAnyBytes returns whether or not the path contains
 // any of the listed bytes.
 func (p Path) FileContainsAnyBytes(subslices [][]byte) (bool, error) {
-	return afero.FileContainsAnyBytes(p.Fs(), p.String(), subslices)
+	contains, err := afero.FileContainsAnyBytes(p.Fs(), p.String(), subslices)
+	return contains, p.pathError("containsbytes", err)
 }
 
 // FileContainsBytes returns whether or not the given file contains the bytes
 func (p Path) FileContainsBytes(subslice []byte) (bool, error) {
-	return afero.FileContainsBytes(p.Fs(), p.String(), subslice)
+	contains, err := afero.FileContainsBytes(p.Fs(), p.String(), subslice)
+	return contains, p.pathError("containsbytes", err)
 }
 
 // IsDir checks if a given path is a directory.
 func (p Path) IsDir() (bool, error) {
-	return afero.IsDir(p.Fs(), p.String())
+	isDir, err := afero.IsDir(p.Fs(), p.String())
+	return isDir, p.pathError("isdir", err)
 }
 
 // IsDir returns whether or not the os.FileMode object represents a
@@ -271,7 +279,12 @@ func IsDir(mode os.FileMode) bool {
 
 // IsEmpty checks if a given file or directory is empty.
 func (p Path) IsEmpty() (bool, error) {
-	return afero.IsEmpty(p.Fs(), p.String())
+	// afero reports a missing path with an error of its own
+	if _, err := p.Fs().Stat(p.String()); err != nil {
+		return false, p.pathError("isempty", err)
+	}
+	isEmpty, err := afero.IsEmpty(p.Fs(), p.String())
+	return isEmpty, p.pathError("isempty", err)
 }
 
 // ReadDir reads the current path and returns a list of the corresponding
@@ -285,9 +298,10 @@ func (p Path) ReadDir() ([]Path, error) {
 	if err != nil {
 		return paths, err
 	}
+	defer handle.Close()
 	children, err := handle.Readdirnames(-1)
 	if err != nil {
-		return paths, err
+		return paths, p.pathError("readdir", err)
 	}
 	for _, child := range children {
 		paths = append(paths, p.Join(child))
@@ -298,12 +312,21 @@ func (p Path) ReadDir() ([]Path, error) {
 // ReadFile reads the given path and returns the data. If the file doesn't exist
 // or is a directory, an error is returned.
 func (p Path) ReadFile() ([]byte, error) {
-	return afero.ReadFile(p.Fs(), p.String())
+	data, err := afero.ReadFile(p.Fs(), p.String())
+	return data, p.pathError("read", err)
 }
 
 // SafeWriteReader is the same as WriteReader but checks to see if file/directory already exists.
 func (p Path) SafeWriteReader(r io.Reader) error {
-	return afero.SafeWriteReader(p.Fs(), p.String(), r)
+	// afero reports an existing path with an error of its own
+	exists, err := p.Exists()
+	if err != nil {
+		return err
+	}
+	if exists {
+		return p.pathError("write", fs.ErrExist)
+	}
+	return p.pathError("write", afero.SafeWriteReader(p.Fs(), p.String(), r))
 }
 
 // WriteFile writes the given data to the path (if possible). If the file exists,
@@ -314,12 +337,12 @@ func (p Path) WriteFile(data []byte, perm ...os.FileMode) error {
 	if len(perm) > 0 {
 		mode = perm[0]
 	}
-	return afero.WriteFile(p.Fs(), p.String(), data, mode)
+	return p.pathError("write", afero.WriteFile(p.Fs(), p.String(), data, mode))
 }
 
 // WriteReader takes a reader and writes the content
 func (p Path) WriteReader(r io.Reader) error {
-	return afero.WriteReader(p.Fs(), p.String(), r)
+	return p.pathError("write", afero.WriteReader(p.Fs(), p.String(), r))
 }
 
 // -----------------------------------------------------------------------------
@@ -332,7 +355,7 @@ func (p Path) WriteReader(r io.Reader) error {
 func (p Path) WithName(name string) (Path, error) {
 	pp, err := p.PurePath.WithName(name)
 	if err != nil {
-		return Path{}, err
+		return Path{}, p.pathError("withname", err)
 	}
 	return copyPathWithPurePath(p, pp), nil
 }
@@ -348,7 +371,7 @@ func (p Path) WithStem(stem string) (Path, error) {
 func (p Path) WithSuffix(suffix string) (Path, error) {
 	pp, err := p.PurePath.WithSuffix(suffix)
 	if err != nil {
-		return Path{}, err
+		return Path{}, p.pathError("withsuffix", err)
 	}
 	return copyPathWithPurePath(p, pp), nil
 }
@@ -389,7 +412,7 @@ func (p Path) Parents() []Path {
 func (p Path) RelativeTo(others ...string) (Path, error) {
 	pp, err := p.PurePath.RelativeTo(others...)
 	if err != nil {
-		return Path{}, err
+		return Path{}, p.pathError("relativeto", err)
 	}
 	return copyPathWithPurePath(p, pp), nil
 }
@@ -404,7 +427,7 @@ func (p Path) RelativeToPath(others ...Path) (Path, error) {
 	}
 	pp, err := p.PurePath.RelativeTo(othersStr...)
 	if err != nil {
-		return Path{}, err
+		return Path{}, p.pathError("relativeto", err)
 	}
 	return copyPathWithPurePath(p, pp), nil
 }
@@ -422,56 +445,104 @@ func (p Path) RelativeToPath(others ...Path) (Path, error) {
 func (p Path) Readlink() (Path, error) {
 	linkReader, ok := p.Fs().(afero.LinkReader)
 	if !ok {
-		return Path{}, p.doesNotImplementErr("afero.LinkReader")
+		return Path{}, p.pathError("readlink", p.doesNotImplementErr("afero.LinkReader"))
 	}
 
 	resolvedPathStr, err := linkReader.ReadlinkIfPossible(p.String())
 	if err != nil {
-		return Path{}, err
+		return Path{}, p.pathError("readlink", err)
 	}
 	return copyPathWithPaths(p, resolvedPathStr), nil
 }
 
-func resolveIfSymlink(path Path) (Path, bool, error) {
-	isSymlink, err := path.IsSymlink()
-	if err != nil {
-		return path, isSymlink, err
+// resolve canonicalizes the path by following symlinks component by
+// component and by normalizing "." and ".." components after resolution. If
+// strict is false, the existing prefix of the path is resolved and the
+// remaining components are appended lexically. At most MaxSymlinkHops symlinks
+// are followed; exceeding them yields an ErrSymlinkLoop.
+func (p Path) resolve(strict bool) (Path, error) {
+	anchor := p.Anchor()
+	parts := p.Parts()
+	if anchor != "" {
+		parts = parts[1:]
 	}
-	if isSymlink {
-		resolvedPath, err := path.Readlink()
-		if err != nil {
-			// Return the path unchanged on errors
-			return path, isSymlink, err
-		}
-		return resolvedPath, isSymlink, nil
-	}
-	return path, isSymlink, nil
-}
 
-func resolveAllHelper(path Path) (Path, error) {
-	parts := path.Parts()
+	// rest holds the components that still need to be resolved
+	rest := make([]string, len(parts))
+	copy(rest, parts)
+	resolved := make([]string, 0, len(parts))
+	hops := 0
+	missing := false
 
-	for i := 0; i < len(parts); i++ {
-		rightOfComponent := parts[i+1:]
-		upToComponent := parts[:i+1]
+	for len(rest) > 0 {
+		name := rest[0]
+		rest = rest[1:]
 
-		componentPath := copyPathWithPaths(path, upToComponent...)
-		resolved, isSymlink, err := resolveIfSymlink(componentPath)
-		if err != nil {
-			return path, err
+		if name == "" || name == "." {
+			continue
+		}
+		if name == ".." {
+			if len(resolved) > 0 && resolved[len(resolved)-1] != ".." {
+				resolved = resolved[:len(resolved)-1]
+			} else if anchor == "" {
+				resolved = append(resolved, name)
+			}
+			continue
+		}
+		if missing {
+			resolved = append(resolved, name)
+			continue
 		}
 
-		if isSymlink {
-			if resolved.IsAbsolute() {
-				return resolveAllHelper(resolved.Join(rightOfComponent...))
+		candidate := copyPathWithPaths(p, append([]string{anchor}, append(resolved, name)...)...)
+		info, err := candidate.lstatIfPossible()
+		if err != nil {
+			if !strict && errors.Is(err, fs.ErrNotExist) {
+				missing = true
+				resolved = append(resolved, name)
+				continue
 			}
-			return resolveAllHelper(componentPath.Parent().JoinPath(resolved).Join(rightOfComponent...))
+			return p, err
+		}
+		if !IsSymlink(info.Mode()) {
+			resolved = append(resolved, name)
+			continue
+		}
+
+		hops++
+		if hops > MaxSymlinkHops {
+			return p, p.pathError("resolve", ErrSymlinkLoop)
 		}
+		target, err := candidate.Readlink()
+		if err != nil {
+			return p, err
+		}
+		targetParts := target.Parts()
+		if target.Anchor() != "" {
+			anchor = target.Anchor()
+			targetParts = targetParts[1:]
+			resolved = resolved[:0]
+		}
+		rest = append(append([]string{}, targetParts...), rest...)
 	}
 
-	// If we get through the entire iteration above, that means no component was a symlink.
-	// Return the argument.
-	return path, nil
+	return copyPathWithPaths(p, append([]string{anchor}, resolved...)...), nil
+}
+
+// Resolve makes the path absolute, resolving all symlinks on the way and
+// normalizing "." and ".." components, like Python's Path.resolve. If strict
+// is true, the path must exist; otherwise the existing prefix of the path is
+// resolved and the remainder is appended without checking whether it exists.
+// Symlink loops are detected and reported as ErrSymlinkLoop.
+//
+// Relative paths are made absolute using the working directory of the process
+// if the path belongs to an OsFs, and are resolved as they are otherwise.
+func (p Path) Resolve(strict bool) (Path, error) {
+	abs, err := p.absolute()
+	if err != nil {
+		return p, err
+	}
+	return abs.resolve(strict)
 }
 
 // ResolveAll canonicalizes the path by following every symlink in
@@ -480,7 +551,44 @@ func resolveAllHelper(path Path) (Path, error) {
 // This will fail if the underlying afero filesystem does not implement
 // afero.LinkReader. The path will be returned unchanged on errors.
 func (p Path) ResolveAll() (Path, error) {
-	return resolveAllHelper(p)
+	return p.resolve(true)
+}
+
+// absolute returns the path joined to the working directory of the process if
+// it is relative and belongs to an OsFs.
+func (p Path) absolute() (Path, error) {
+	if p.IsAbsolute() || !isOsFs(p.Fs()) {
+		return p, nil
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return p, p.pathError("getwd", err)
+	}
+	return copyPathWithPaths(p, cwd, p.String()), nil
+}
+
+// isOsFs returns whether the given filesystem is afero's OsFs.
+func isOsFs(fs afero.Fs) bool {
+	switch fs.(type) {
+	case *afero.OsFs, afero.OsFs:
+		return true
+	}
+	return false
+}
+
+// sameFs returns whether a and b are the same afero filesystem. All OsFs
+// values are the same filesystem. Filesystems that can't be compared, as
+// their dynamic type holds a map or slice, are never the same.
+func sameFs(a, b afero.Fs) (same bool) {
+	if isOsFs(a) && isOsFs(b) {
+		return true
+	}
+	defer func() {
+		if recover() != nil {
+			same = false
+		}
+	}()
+	return a == b
 }
 
 // Lstat lstat's the path if the underlying afero filesystem supports it. If
@@ -491,13 +599,13 @@ func (p Path) ResolveAll() (Path, error) {
 func (p Path) Lstat() (os.FileInfo, error) {
 	lStater, ok := p.Fs().(afero.Lstater)
 	if !ok {
-		return nil, p.doesNotImplementErr("afero.Lstater")
+		return nil, p.pathError("lstat", p.doesNotImplementErr("afero.Lstater"))
 	}
 	stat, lstatCalled, err := lStater.LstatIfPossible(p.String())
 	if !lstatCalled && err == nil {
-		return nil, p.lstatNotPossible()
+		return nil, p.pathError("lstat", p.lstatNotPossible())
 	}
-	return stat, err
+	return stat, p.pathError("lstat", err)
 }
 
 // -----------------------------------------------------------------------------
@@ -517,10 +625,10 @@ func (p Path) SymlinkStr(target string) error {
 func (p Path) Symlink(target Path) error {
 	symlinker, ok := p.fs.(afero.Linker)
 	if !ok {
-		return p.doesNotImplementErr("afero.Linker")
+		return p.pathError("symlink", p.doesNotImplementErr("afero.Linker"))
 	}
 
-	return symlinker.SymlinkIfPossible(target.String(), p.String())
+	return p.pathError("symlink", symlinker.SymlinkIfPossible(target.String(), p.String()))
 }
 
 // -----------------------------------------------------------------------------
@@ -586,35 +694,48 @@ func (p Path) Equals(other Path) bool {
 // works if this path is a directory and it exists. If the directory is empty,
 // an ErrDirectoryEmpty will be returned.
 func (p Path) GetLatest() (Path, error) {
-	files, err := p.ReadDir()
+	return p.firstSorted("getlatest", SortByMtime, Descending)
+}
+
+// GetOldest returns the file or directory that has the least recent mtime.
+// Only works if this path is a directory and it exists. If the directory is
+// empty, an ErrDirectoryEmpty will be returned.
+func (p Path) GetOldest() (Path, error) {
+	return p.firstSorted("getoldest", SortByMtime, Ascending)
+}
+
+// GetLargest returns the file or directory that has the largest size. Only
+// works if this path is a directory and it exists. If the directory is empty,
+// an ErrDirectoryEmpty will be returned.
+func (p Path) GetLargest() (Path, error) {
+	return p.firstSorted("getlargest", SortBySize, Descending)
+}
+
+// Newest returns up to n files or directories with the most recent mtime,
+// newest first. If n <= 0, all of them are returned. Only works if this path
+// is a directory and it exists.
+func (p Path) Newest(n int) ([]Path, error) {
+	entries, err := p.ReadDirSorted(SortByMtime, Descending, nil)
 	if err != nil {
-		return Path{}, err
+		return nil, err
 	}
-	if len(files) == 0 {
-		return Path{}, ErrDirectoryEmpty
+	if n > 0 && n < len(entries) {
+		entries = entries[:n]
 	}
+	return entries, nil
+}
 
-	greatestFileSeen := p.Join(files[0].Name())
-	for i := 1; i < len(files)-1; i++ {
-		file := files[i]
-		greatestMtime, err := greatestFileSeen.Mtime()
-		if err != nil {
-			return Path{}, err
-		}
-
-		thisMtime, err := file.Mtime()
-		// There is a possible race condition where the file is deleted after
-		// our call to ReadDir. We throw away the error if it isn't
-		// os.ErrNotExist
-		if err != nil && !os.IsNotExist(err) {
-			return Path{}, err
-		}
-		if thisMtime.After(greatestMtime) {
-			greatestFileSeen = p.Join(file.Name())
-		}
+// firstSorted returns the first entry of the directory in the given sort
+// order.
+func (p Path) firstSorted(op string, by SortKey, order SortOrder) (Path, error) {
+	entries, err := p.ReadDirSorted(by, order, nil)
+	if err != nil {
+		return Path{}, err
 	}
-
-	return greatestFileSeen, nil
+	if len(entries) == 0 {
+		return Path{}, p.pathError(op, ErrDirectoryEmpty)
+	}
+	return entries[0], nil
 }
 
 // Glob returns all matches of pattern relative to this object's path.
@@ -622,7 +743,7 @@ func (p Path) Glob(pattern string) ([]Path, error) {
 	pattern = strings.Join([]string{p.String(), pattern}, "/")
 	matches, err := afero.Glob(p.fs, pattern)
 	if err != nil {
-		return nil, fmt.Errorf("failed to glob: %w", err)
+		return nil, p.pathError("glob", err)
 	}
 
 	pathMatches := []Path{}
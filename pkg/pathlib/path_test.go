@@ -4,7 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -22,15 +22,32 @@ func setupPathTest(t *testing.T) (testutils.Assertions, testutils.Assertions, Pa
 	// We actually can't use the MemMapFs because some of the tests
 	// are testing symlink behavior. We might want to split these
 	// tests out to use MemMapFs when possible.
-	tmpdir, err := ioutil.TempDir("", "")
+	tmpdir, err := TempDir(NewPath(), "")
 	testutils.NewRequire(t).NoError(err)
-	return assert, require, NewPath(tmpdir)
+	return assert, require, tmpdir
 }
 
 func teardownPathTest(t *testing.T, tmpdir Path) {
 	testutils.NewAssert(t).NoError(tmpdir.RemoveAll())
 }
 
+// tempDirForTest creates a new temporary directory on the given afero
+// filesystem, which is removed when the test completes. It mirrors
+// pathlibtest.TempDir, which can't be imported here.
+func tempDirForTest(tb testing.TB, fs afero.Fs) Path {
+	tb.Helper()
+	dir, err := TempDir(NewPathWithFS(fs), "pathlib")
+	if err != nil {
+		tb.Fatalf("failed to create temporary directory: %v", err)
+	}
+	tb.Cleanup(func() {
+		if err := dir.RemoveAll(); err != nil {
+			tb.Errorf("failed to remove temporary directory: %v", err)
+		}
+	})
+	return dir
+}
+
 func TestSymlink(t *testing.T) {
 	assert, require, tmpdir := setupPathTest(t)
 	defer teardownPathTest(t, tmpdir)
@@ -340,6 +357,74 @@ func TestResolveAllAbsolute(t *testing.T) {
 		strings.Join(resolvedParts[len(resolvedParts)-6:], resolved.flavor.Separator()))
 }
 
+func TestResolveSymlinkLoop(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	require.NoError(tmpdir.Join("a").Symlink(NewPath("b")))
+	require.NoError(tmpdir.Join("b").Symlink(NewPath("a")))
+	require.NoError(tmpdir.Join("self").Symlink(NewPath("self/child")))
+
+	for _, strict := range []bool{true, false} {
+		_, err := tmpdir.Join("a").Resolve(strict)
+		assert.EqualError(ErrSymlinkLoop, err)
+		_, err = tmpdir.Join("self", "x").Resolve(strict)
+		assert.EqualError(ErrSymlinkLoop, err)
+	}
+	_, err := tmpdir.Join("a").ResolveAll()
+	assert.EqualError(ErrSymlinkLoop, err)
+}
+
+func TestResolveStrict(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	realTmpdir, err := tmpdir.Resolve(true)
+	require.NoError(err)
+	require.NoError(tmpdir.Join("dir", "sub").MkdirAll())
+	require.NoError(tmpdir.Join("link").Symlink(NewPath("dir/sub")))
+
+	// ".." is applied after resolving the symlink
+	resolved, err := tmpdir.Join("link", "..", "sub", ".", "..").Resolve(true)
+	require.NoError(err)
+	assert.Equal(realTmpdir.Join("dir").String(), resolved.String())
+
+	_, err = tmpdir.Join("link", "missing", "file").Resolve(true)
+	assert.True(errors.Is(err, fs.ErrNotExist))
+
+	resolved, err = tmpdir.Join("link", "missing", "..", "file").Resolve(false)
+	require.NoError(err)
+	assert.Equal(realTmpdir.Join("dir", "sub", "file").String(), resolved.String())
+}
+
+func TestResolveRelative(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	cwd, err := os.Getwd()
+	require.NoError(err)
+	defer os.Chdir(cwd)
+	require.NoError(os.Chdir(tmpdir.String()))
+	realTmpdir, err := tmpdir.Resolve(true)
+	require.NoError(err)
+
+	resolved, err := NewPath("a", "b").Resolve(false)
+	require.NoError(err)
+	assert.True(resolved.IsAbsolute())
+	assert.Equal(realTmpdir.Join("a", "b").String(), resolved.String())
+}
+
+func TestResolveMemMapFs(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	root := NewPathWithFS(afero.NewMemMapFs(), "/root")
+	require.NoError(root.Join("dir").MkdirAll())
+
+	resolved, err := root.Join("dir", "..", "dir").Resolve(true)
+	require.NoError(err)
+	assert.Equal("/root/dir", resolved.String())
+
+	_, err = root.Join("missing").Resolve(true)
+	assert.True(errors.Is(err, fs.ErrNotExist))
+}
+
 func TestEquals(t *testing.T) {
 	assert, require, tmpdir := setupPathTest(t)
 	defer teardownPathTest(t, tmpdir)
@@ -0,0 +1,27 @@
+// Package pathlibtest provides helpers for using pathlib in tests and
+// benchmarks.
+package pathlibtest
+
+import (
+	"testing"
+
+	"github.com/aisbergg/go-pathlib/pkg/pathlib"
+	"github.com/spf13/afero"
+)
+
+// TempDir creates a new temporary directory on the given afero filesystem for
+// use in tests and benchmarks. The directory and its content are removed
+// automatically when the test completes. Any error fails the test immediately.
+func TempDir(tb testing.TB, fs afero.Fs) pathlib.Path {
+	tb.Helper()
+	dir, err := pathlib.TempDir(pathlib.NewPathWithFS(fs), "pathlib")
+	if err != nil {
+		tb.Fatalf("failed to create temporary directory: %v", err)
+	}
+	tb.Cleanup(func() {
+		if err := dir.RemoveAll(); err != nil {
+			tb.Errorf("failed to remove temporary directory: %v", err)
+		}
+	})
+	return dir
+}
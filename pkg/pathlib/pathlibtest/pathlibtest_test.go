@@ -0,0 +1,24 @@
+package pathlibtest
+
+import (
+	"testing"
+
+	"github.com/aisbergg/go-pathlib/internal/testutils"
+	"github.com/aisbergg/go-pathlib/pkg/pathlib"
+	"github.com/spf13/afero"
+)
+
+func TestTempDir(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	fs := afero.NewMemMapFs()
+	var dir pathlib.Path
+	t.Run("sub", func(t *testing.T) {
+		dir = TempDir(t, fs)
+		exists, err := dir.Exists()
+		assert.NoError(err)
+		assert.True(exists)
+	})
+	exists, err := dir.Exists()
+	assert.NoError(err)
+	assert.False(exists)
+}
@@ -2,11 +2,16 @@ package pathlib
 
 import (
 	"errors"
+	"fmt"
 	"path/filepath"
 	"runtime"
 	"strings"
 )
 
+// errNoOtherPath is returned by the methods that compare the path against
+// others if none are given.
+var errNoOtherPath = errors.New("at least one other path must be provided")
+
 // PurePath represents a filesystem path and offers methods for manipulating it
 // without any I/O operations.
 type PurePath struct {
@@ -191,14 +196,14 @@ func (p PurePath) Stem() string {
 // WithName returns a new path with the file name changed.
 func (p PurePath) WithName(name string) (PurePath, error) {
 	if p.Name() == "" {
-		return PurePath{}, errors.New("path has an empty name")
+		return PurePath{}, p.pathError("withname", ErrEmptyName)
 	}
 	drive, root, parts := parseParts([]string{name}, p.flavor)
 	if name == "" ||
 		name[len(name)-1:] == p.flavor.Separator() ||
 		name[len(name)-1:] == p.flavor.AltSeparator() ||
 		drive != "" || root != "" || len(parts) != 1 {
-		return PurePath{}, errors.New("invalid name")
+		return PurePath{}, p.pathError("withname", fmt.Errorf("%w: %q", ErrInvalidName, name))
 	}
 	// need to create a array to avoid modifying the original
 	parts = make([]string, len(p.parts))
@@ -218,11 +223,11 @@ func (p PurePath) WithSuffix(suffix string) (PurePath, error) {
 	if (suffix != "" && (!strings.HasPrefix(suffix, ".") || suffix == ".")) ||
 		strings.Contains(suffix, p.flavor.Separator()) ||
 		(p.flavor.AltSeparator() != "" && strings.Contains(suffix, p.flavor.AltSeparator())) {
-		return PurePath{}, errors.New("invalid suffix")
+		return PurePath{}, p.pathError("withsuffix", fmt.Errorf("%w: %q", ErrInvalidSuffix, suffix))
 	}
 	name := p.Name()
 	if name == "" {
-		return PurePath{}, errors.New("path has an empty name")
+		return PurePath{}, p.pathError("withsuffix", ErrEmptyName)
 	}
 	oldSuffix := p.Suffix()
 	if oldSuffix == "" {
@@ -293,7 +298,7 @@ func (p PurePath) Parents() (parents []PurePath) {
 // returned Path object will represent to/foo.txt.
 func (p PurePath) RelativeTo(others ...string) (PurePath, error) {
 	if len(others) == 0 {
-		return PurePath{}, errors.New("at least one other path must be provided")
+		return PurePath{}, p.pathError("relativeto", errNoOtherPath)
 	}
 	drive, root, parts := p.drive, p.root, p.parts
 	var absParts []string
@@ -336,10 +341,10 @@ func (p PurePath) RelativeTo(others ...string) (PurePath, error) {
 	n := len(toAbsParts)
 	if n == 0 {
 		if drive != "" || root != "" {
-			return PurePath{}, errors.New("path is not relative")
+			return PurePath{}, p.pathError("relativeto", fmt.Errorf("%w to %s", ErrNotRelative, toPath))
 		}
 	} else if !casefoldComp(absParts, toAbsParts) {
-		return PurePath{}, errors.New("path is not relative")
+		return PurePath{}, p.pathError("relativeto", fmt.Errorf("%w to %s", ErrNotRelative, toPath))
 	}
 	if n != 1 {
 		root = ""
@@ -352,7 +357,7 @@ func (p PurePath) RelativeTo(others ...string) (PurePath, error) {
 // returned Path object will represent to/foo.txt.
 func (p PurePath) RelativeToPath(others ...PurePath) (PurePath, error) {
 	if len(others) == 0 {
-		return PurePath{}, errors.New("at least one other path must be provided")
+		return PurePath{}, p.pathError("relativeto", errNoOtherPath)
 	}
 	if len(others) > 1 {
 		othersStr := make([]string, 0, len(others))
@@ -367,7 +372,7 @@ func (p PurePath) RelativeToPath(others ...PurePath) (PurePath, error) {
 // IsRelativeTo returns whether or not the path is relative to the other path.
 func (p PurePath) IsRelativeTo(other ...string) (bool, error) {
 	if len(other) == 0 {
-		return false, errors.New("at least one other path must be provided")
+		return false, p.pathError("isrelativeto", errNoOtherPath)
 	}
 	_, err := p.RelativeTo(other...)
 	return err == nil, nil
@@ -376,7 +381,7 @@ func (p PurePath) IsRelativeTo(other ...string) (bool, error) {
 // IsRelativeToPath returns whether or not the path is relative to the other path.
 func (p PurePath) IsRelativeToPath(other ...PurePath) (bool, error) {
 	if len(other) == 0 {
-		return false, errors.New("at least one other path must be provided")
+		return false, p.pathError("isrelativeto", errNoOtherPath)
 	}
 	_, err := p.RelativeToPath(other...)
 	return err == nil, nil
@@ -0,0 +1,127 @@
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DirIterator iterates over the entries of a directory, reading them in
+// batches of DirBatchSize entries. Only a single batch of names is held in
+// memory at a time. It is not safe for concurrent use.
+//
+// The underlying directory handle is closed once the iteration is exhausted
+// or fails. Call Close to stop an iteration early.
+//
+//	it, err := dir.ReadDirIter()
+//	if err != nil {
+//		return err
+//	}
+//	defer it.Close()
+//	for it.Next() {
+//		fmt.Println(it.Path())
+//	}
+//	return it.Err()
+type DirIterator struct {
+	dir    Path
+	handle *File
+	batch  int
+
+	names   []string
+	current Path
+	err     error
+}
+
+// ReadDirIter returns an iterator over the entries of the directory. Like
+// ReadDir, it doesn't stat the entries.
+func (p Path) ReadDirIter() (*DirIterator, error) {
+	handle, err := p.Open()
+	if err != nil {
+		return nil, err
+	}
+	batch := DirBatchSize
+	if batch <= 0 {
+		batch = 1
+	}
+	return &DirIterator{dir: p, handle: handle, batch: batch}, nil
+}
+
+// Next advances the iterator to the next entry, which is then available
+// through Path. It returns false once all entries have been read or an error
+// occurred, see Err.
+func (it *DirIterator) Next() bool {
+	for len(it.names) == 0 {
+		if it.handle == nil {
+			return false
+		}
+		names, err := it.handle.Readdirnames(it.batch)
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				it.err = it.dir.pathError("readdir", err)
+			}
+			it.Close()
+		}
+		it.names = names
+	}
+	it.current = it.dir.Join(it.names[0])
+	it.names = it.names[1:]
+	return true
+}
+
+// Path returns the path of the current entry.
+func (it *DirIterator) Path() Path {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *DirIterator) Err() error {
+	return it.err
+}
+
+// Close closes the underlying directory handle. Next returns false
+// afterwards. Calling Close more than once has no effect.
+func (it *DirIterator) Close() error {
+	it.names = nil
+	if it.handle == nil {
+		return nil
+	}
+	err := it.handle.Close()
+	it.handle = nil
+	return it.dir.pathError("close", err)
+}
+
+// ReadDirBatch reads the entries of the directory in batches of at most n
+// entries and calls fn with each batch. Only a single batch is held in memory
+// at a time; fn must not retain the slice. If fn returns an error, reading
+// stops and the error is returned. The directory handle is closed before
+// ReadDirBatch returns.
+func (p Path) ReadDirBatch(n int, fn func(batch []Path) error) error {
+	if n <= 0 {
+		return p.pathError("readdir", fmt.Errorf("batch size must be positive, got %d", n))
+	}
+	handle, err := p.Open()
+	if err != nil {
+		return err
+	}
+	defer handle.Close()
+
+	batch := make([]Path, 0, n)
+	for {
+		names, err := handle.Readdirnames(n)
+		for _, name := range names {
+			batch = append(batch, p.Join(name))
+		}
+		if len(batch) > 0 {
+			if ferr := fn(batch); ferr != nil {
+				return ferr
+			}
+			batch = batch[:0]
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return p.pathError("readdir", err)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aisbergg/go-pathlib/internal/testutils"
+	"github.com/spf13/afero"
+)
+
+// handleCountingFs counts the file handles that are currently open.
+type handleCountingFs struct {
+	afero.Fs
+	open int64
+}
+
+func (fs *handleCountingFs) Open(name string) (afero.File, error) {
+	return fs.count(fs.Fs.Open(name))
+}
+
+func (fs *handleCountingFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	return fs.count(fs.Fs.OpenFile(name, flag, perm))
+}
+
+func (fs *handleCountingFs) count(file afero.File, err error) (afero.File, error) {
+	if err != nil {
+		return nil, err
+	}
+	atomic.AddInt64(&fs.open, 1)
+	return &countedFile{File: file, fs: fs}, nil
+}
+
+type countedFile struct {
+	afero.File
+	fs     *handleCountingFs
+	closed int32
+}
+
+func (f *countedFile) Close() error {
+	if atomic.CompareAndSwapInt32(&f.closed, 0, 1) {
+		atomic.AddInt64(&f.fs.open, -1)
+	}
+	return f.File.Close()
+}
+
+func (fs *handleCountingFs) openHandles() int64 {
+	return atomic.LoadInt64(&fs.open)
+}
+
+func setupReadDirTest(t *testing.T, n int) (*handleCountingFs, Path) {
+	fs := &handleCountingFs{Fs: afero.NewMemMapFs()}
+	dir := tempDirForTest(t, fs)
+	testutils.NewRequire(t).NoError(NFiles(dir, n))
+	return fs, dir
+}
+
+func TestReadDirClosesHandle(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	fs, dir := setupReadDirTest(t, 3)
+
+	children, err := dir.ReadDir()
+	require.NoError(err)
+	assert.Equal(3, len(children))
+	_, err = dir.Entries()
+	require.NoError(err)
+	_, err = dir.Join("file0.txt").ReadDir()
+	assert.Error(err)
+	assert.Equal(int64(0), fs.openHandles())
+}
+
+func TestReadDirIter(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	fs, dir := setupReadDirTest(t, 5)
+	defer func(size int) { DirBatchSize = size }(DirBatchSize)
+	DirBatchSize = 2
+
+	it, err := dir.ReadDirIter()
+	require.NoError(err)
+	var names []string
+	for it.Next() {
+		assert.Equal(dir, it.Path().Parent())
+		names = append(names, it.Path().Name())
+	}
+	require.NoError(it.Err())
+	sort.Strings(names)
+	assert.Equal([]string{"file0.txt", "file1.txt", "file2.txt", "file3.txt", "file4.txt"}, names)
+	assert.False(it.Next())
+	assert.Equal(int64(0), fs.openHandles())
+	assert.NoError(it.Close())
+
+	// stopping early
+	it, err = dir.ReadDirIter()
+	require.NoError(err)
+	assert.True(it.Next())
+	assert.Equal(int64(1), fs.openHandles())
+	assert.NoError(it.Close())
+	assert.False(it.Next())
+	assert.Equal(int64(0), fs.openHandles())
+
+	// reading a file fails
+	it, err = dir.Join("file0.txt").ReadDirIter()
+	require.NoError(err)
+	assert.False(it.Next())
+	assert.Error(it.Err())
+	assert.Equal(int64(0), fs.openHandles())
+
+	_, err = dir.Join("i_dont_exist").ReadDirIter()
+	assert.Error(err)
+}
+
+func TestReadDirBatch(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	fs, dir := setupReadDirTest(t, 5)
+
+	var sizes []int
+	total := 0
+	require.NoError(dir.ReadDirBatch(2, func(batch []Path) error {
+		sizes = append(sizes, len(batch))
+		total += len(batch)
+		return nil
+	}))
+	assert.Equal([]int{2, 2, 1}, sizes)
+	assert.Equal(5, total)
+	assert.Equal(int64(0), fs.openHandles())
+
+	stop := fmt.Errorf("stop")
+	calls := 0
+	err := dir.ReadDirBatch(2, func(batch []Path) error {
+		calls++
+		return stop
+	})
+	assert.True(errors.Is(err, stop))
+	assert.Equal(1, calls)
+	assert.Equal(int64(0), fs.openHandles())
+
+	assert.Error(dir.ReadDirBatch(0, func(batch []Path) error { return nil }))
+}
@@ -0,0 +1,135 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// StatField is a bit set of the fields of an ExtendedFileInfo.
+type StatField uint
+
+const (
+	// StatDevice marks the Device field.
+	StatDevice StatField = 1 << iota
+	// StatInode marks the Inode field.
+	StatInode
+	// StatNlink marks the Nlink field.
+	StatNlink
+	// StatUID marks the UID field.
+	StatUID
+	// StatGID marks the GID field.
+	StatGID
+	// StatAtime marks the Atime field.
+	StatAtime
+	// StatCtime marks the Ctime field.
+	StatCtime
+	// StatBtime marks the Btime field.
+	StatBtime
+	// StatBlocks marks the Blocks field.
+	StatBlocks
+)
+
+// ExtendedFileInfo extends os.FileInfo by the information found in the stat
+// structures of the operating system. Which of the fields are populated
+// depends on the afero filesystem and the platform; Known tells them apart
+// from fields whose value is unknown and left at its zero value.
+type ExtendedFileInfo struct {
+	os.FileInfo
+
+	// Device is the ID of the device containing the file.
+	Device uint64
+	// Inode is the inode number of the file.
+	Inode uint64
+	// Nlink is the number of hard links to the file.
+	Nlink uint64
+	// Blocks is the number of 512-byte blocks allocated for the file.
+	Blocks uint64
+	// UID is the user ID of the owner of the file.
+	UID uint32
+	// GID is the group ID of the owner of the file.
+	GID uint32
+	// Atime is the time of the last access.
+	Atime time.Time
+	// Ctime is the time of the last status change.
+	Ctime time.Time
+	// Btime is the birth (creation) time of the file.
+	Btime time.Time
+
+	// Known is the set of fields that are populated.
+	Known StatField
+}
+
+// Has returns whether all of the given fields are known.
+func (x *ExtendedFileInfo) Has(fields StatField) bool {
+	return x.Known&fields == fields
+}
+
+// NewExtendedFileInfo returns an ExtendedFileInfo populated with the
+// information found in the os.FileInfo. The birth time is only known on some
+// platforms; use Path.StatEx to get it wherever possible.
+func NewExtendedFileInfo(info os.FileInfo) *ExtendedFileInfo {
+	x := &ExtendedFileInfo{FileInfo: info}
+	fillExtendedFileInfo(x)
+	return x
+}
+
+// StatEx returns the extended stat information of the path. Symlinks are
+// followed. For OsFs the information is taken from the stat structure of the
+// operating system (and statx(2) on Linux for the birth time), other afero
+// filesystems usually only provide the basic os.FileInfo.
+func (p Path) StatEx() (*ExtendedFileInfo, error) {
+	info, err := p.Stat()
+	if err != nil {
+		return nil, err
+	}
+	x := NewExtendedFileInfo(info)
+	if !x.Has(StatBtime) && isOsFs(p.Fs()) {
+		if btime, ok := birthTime(p.String()); ok {
+			x.Btime = btime
+			x.Known |= StatBtime
+		}
+	}
+	return x, nil
+}
+
+// Atime returns the access time of the given path.
+func (p Path) Atime() (time.Time, error) {
+	stat, err := p.Stat()
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, err := Atime(stat)
+	return t, p.pathError("stat", err)
+}
+
+// Atime returns the access time described in the given os.FileInfo object. An
+// ErrStatUnavailable is returned if it doesn't include the access time.
+func Atime(fileInfo os.FileInfo) (time.Time, error) {
+	x := NewExtendedFileInfo(fileInfo)
+	if !x.Has(StatAtime) {
+		return time.Time{}, fmt.Errorf("%w: access time", ErrStatUnavailable)
+	}
+	return x.Atime, nil
+}
+
+// Ctime returns the status change time of the given path.
+func (p Path) Ctime() (time.Time, error) {
+	stat, err := p.Stat()
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, err := Ctime(stat)
+	return t, p.pathError("stat", err)
+}
+
+// Ctime returns the status change time described in the given os.FileInfo
+// object. An ErrStatUnavailable is returned if it doesn't include the status
+// change time.
+func Ctime(fileInfo os.FileInfo) (time.Time, error) {
+	x := NewExtendedFileInfo(fileInfo)
+	if !x.Has(StatCtime) {
+		return time.Time{}, fmt.Errorf("%w: status change time", ErrStatUnavailable)
+	}
+	return x.Ctime, nil
+}
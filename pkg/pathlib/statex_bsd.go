@@ -0,0 +1,36 @@
+//go:build darwin || freebsd || netbsd
+// +build darwin freebsd netbsd
+
+package pathlib
+
+import (
+	"syscall"
+	"time"
+)
+
+// fillExtendedFileInfo populates the ExtendedFileInfo from a syscall.Stat_t.
+func fillExtendedFileInfo(x *ExtendedFileInfo) {
+	stat, ok := x.Sys().(*syscall.Stat_t)
+	if !ok || stat == nil {
+		return
+	}
+	x.Device = uint64(stat.Dev)
+	x.Inode = uint64(stat.Ino)
+	x.Nlink = uint64(stat.Nlink)
+	x.Blocks = uint64(stat.Blocks)
+	x.UID = stat.Uid
+	x.GID = stat.Gid
+	x.Atime = time.Unix(int64(stat.Atimespec.Sec), int64(stat.Atimespec.Nsec))
+	x.Ctime = time.Unix(int64(stat.Ctimespec.Sec), int64(stat.Ctimespec.Nsec))
+	x.Known |= StatDevice | StatInode | StatNlink | StatBlocks | StatUID | StatGID | StatAtime | StatCtime
+	// filesystems without birth times report -1 or 0
+	if stat.Birthtimespec.Sec > 0 {
+		x.Btime = time.Unix(int64(stat.Birthtimespec.Sec), int64(stat.Birthtimespec.Nsec))
+		x.Known |= StatBtime
+	}
+}
+
+// birthTime is provided by fillExtendedFileInfo on this platform.
+func birthTime(path string) (time.Time, bool) {
+	return time.Time{}, false
+}
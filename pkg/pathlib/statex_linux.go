@@ -0,0 +1,105 @@
+package pathlib
+
+import (
+	"runtime"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// fillExtendedFileInfo populates the ExtendedFileInfo from a syscall.Stat_t.
+func fillExtendedFileInfo(x *ExtendedFileInfo) {
+	stat, ok := x.Sys().(*syscall.Stat_t)
+	if !ok || stat == nil {
+		return
+	}
+	x.Device = uint64(stat.Dev)
+	x.Inode = uint64(stat.Ino)
+	x.Nlink = uint64(stat.Nlink)
+	x.Blocks = uint64(stat.Blocks)
+	x.UID = stat.Uid
+	x.GID = stat.Gid
+	x.Atime = time.Unix(int64(stat.Atim.Sec), int64(stat.Atim.Nsec))
+	x.Ctime = time.Unix(int64(stat.Ctim.Sec), int64(stat.Ctim.Nsec))
+	x.Known |= StatDevice | StatInode | StatNlink | StatBlocks | StatUID | StatGID | StatAtime | StatCtime
+}
+
+const (
+	atFdcwd    = -0x64
+	statxBtime = 0x800
+)
+
+// statxTimestamp mirrors struct statx_timestamp of the Linux kernel.
+type statxTimestamp struct {
+	Sec      int64
+	Nsec     uint32
+	reserved int32
+}
+
+// statxT mirrors struct statx of the Linux kernel, which has the same layout
+// on all architectures.
+type statxT struct {
+	Mask           uint32
+	Blksize        uint32
+	Attributes     uint64
+	Nlink          uint32
+	UID            uint32
+	GID            uint32
+	Mode           uint16
+	spare0         uint16
+	Ino            uint64
+	Size           uint64
+	Blocks         uint64
+	AttributesMask uint64
+	Atime          statxTimestamp
+	Btime          statxTimestamp
+	Ctime          statxTimestamp
+	Mtime          statxTimestamp
+	RdevMajor      uint32
+	RdevMinor      uint32
+	DevMajor       uint32
+	DevMinor       uint32
+	spare2         [14]uint64
+}
+
+// statxTrap returns the number of the statx syscall, which is not part of the
+// syscall package.
+func statxTrap() (uintptr, bool) {
+	switch runtime.GOARCH {
+	case "amd64":
+		return 332, true
+	case "386", "ppc64", "ppc64le":
+		return 383, true
+	case "arm":
+		return 397, true
+	case "arm64", "riscv64", "loong64":
+		return 291, true
+	case "s390x":
+		return 379, true
+	case "mips", "mipsle":
+		return 4366, true
+	case "mips64", "mips64le":
+		return 5326, true
+	}
+	return 0, false
+}
+
+// birthTime returns the birth time of the file using statx(2). The second
+// return value is false if the kernel or filesystem doesn't provide it.
+func birthTime(path string) (time.Time, bool) {
+	trap, ok := statxTrap()
+	if !ok {
+		return time.Time{}, false
+	}
+	pathPtr, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	var stx statxT
+	dirfd := atFdcwd
+	_, _, errno := syscall.Syscall6(trap, uintptr(dirfd), uintptr(unsafe.Pointer(pathPtr)), 0, statxBtime, uintptr(unsafe.Pointer(&stx)), 0)
+	if errno != 0 || stx.Mask&statxBtime == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(stx.Btime.Sec, int64(stx.Btime.Nsec)), true
+}
@@ -0,0 +1,14 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris && !windows
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris,!windows
+
+package pathlib
+
+import "time"
+
+// fillExtendedFileInfo is not supported on this platform.
+func fillExtendedFileInfo(x *ExtendedFileInfo) {}
+
+// birthTime is not supported on this platform.
+func birthTime(path string) (time.Time, bool) {
+	return time.Time{}, false
+}
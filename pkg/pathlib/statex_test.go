@@ -0,0 +1,71 @@
+package pathlib
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/aisbergg/go-pathlib/internal/testutils"
+	"github.com/spf13/afero"
+)
+
+func TestStatEx(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("stat structures are not available on Windows")
+	}
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	file := tmpdir.Join("file.txt")
+	require.NoError(file.WriteFile([]byte("hello")))
+	require.NoError(os.Link(file.String(), tmpdir.Join("hardlink.txt").String()))
+	atime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(file.Chtimes(atime, atime))
+
+	stat, err := file.StatEx()
+	require.NoError(err)
+	assert.True(stat.Has(StatDevice | StatInode | StatNlink | StatUID | StatGID | StatAtime | StatCtime))
+	assert.Equal("file.txt", stat.Name())
+	assert.Equal(int64(5), stat.Size())
+	assert.Equal(uint64(2), stat.Nlink)
+	assert.Equal(uint32(os.Getuid()), stat.UID)
+	assert.True(stat.Atime.Equal(atime))
+	assert.True(time.Since(stat.Ctime) < time.Hour)
+	if stat.Has(StatBtime) {
+		assert.True(time.Since(stat.Btime) < time.Hour)
+	}
+
+	id, err := file.FileID()
+	require.NoError(err)
+	assert.Equal(FileID{Device: stat.Device, Inode: stat.Inode}, id)
+
+	fileAtime, err := file.Atime()
+	require.NoError(err)
+	assert.True(fileAtime.Equal(atime))
+	ctime, err := file.Ctime()
+	require.NoError(err)
+	assert.True(ctime.Equal(stat.Ctime))
+}
+
+func TestStatExMemMapFs(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	file := NewPathWithFS(afero.NewMemMapFs(), "/file.txt")
+	require.NoError(file.WriteFile([]byte("hello")))
+
+	stat, err := file.StatEx()
+	require.NoError(err)
+	assert.Equal(StatField(0), stat.Known)
+	assert.False(stat.Has(StatAtime))
+	assert.Equal(int64(5), stat.Size())
+
+	_, err = file.Atime()
+	assert.True(errors.Is(err, ErrStatUnavailable))
+	_, err = file.Ctime()
+	assert.True(errors.Is(err, ErrStatUnavailable))
+
+	_, err = file.Parent().Join("i_dont_exist").StatEx()
+	assert.True(errors.Is(err, fs.ErrNotExist))
+}
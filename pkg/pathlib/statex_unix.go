@@ -0,0 +1,31 @@
+//go:build aix || dragonfly || openbsd || solaris
+// +build aix dragonfly openbsd solaris
+
+package pathlib
+
+import (
+	"syscall"
+	"time"
+)
+
+// fillExtendedFileInfo populates the ExtendedFileInfo from a syscall.Stat_t.
+func fillExtendedFileInfo(x *ExtendedFileInfo) {
+	stat, ok := x.Sys().(*syscall.Stat_t)
+	if !ok || stat == nil {
+		return
+	}
+	x.Device = uint64(stat.Dev)
+	x.Inode = uint64(stat.Ino)
+	x.Nlink = uint64(stat.Nlink)
+	x.Blocks = uint64(stat.Blocks)
+	x.UID = stat.Uid
+	x.GID = stat.Gid
+	x.Atime = time.Unix(int64(stat.Atim.Sec), int64(stat.Atim.Nsec))
+	x.Ctime = time.Unix(int64(stat.Ctim.Sec), int64(stat.Ctim.Nsec))
+	x.Known |= StatDevice | StatInode | StatNlink | StatBlocks | StatUID | StatGID | StatAtime | StatCtime
+}
+
+// birthTime is not supported on this platform.
+func birthTime(path string) (time.Time, bool) {
+	return time.Time{}, false
+}
@@ -0,0 +1,23 @@
+package pathlib
+
+import (
+	"syscall"
+	"time"
+)
+
+// fillExtendedFileInfo populates the ExtendedFileInfo from a
+// syscall.Win32FileAttributeData.
+func fillExtendedFileInfo(x *ExtendedFileInfo) {
+	attrs, ok := x.Sys().(*syscall.Win32FileAttributeData)
+	if !ok || attrs == nil {
+		return
+	}
+	x.Atime = time.Unix(0, attrs.LastAccessTime.Nanoseconds())
+	x.Btime = time.Unix(0, attrs.CreationTime.Nanoseconds())
+	x.Known |= StatAtime | StatBtime
+}
+
+// birthTime is provided by fillExtendedFileInfo on this platform.
+func birthTime(path string) (time.Time, bool) {
+	return time.Time{}, false
+}
@@ -0,0 +1,43 @@
+package pathlib
+
+import (
+	"github.com/spf13/afero"
+)
+
+// tempDirName returns the name of the directory to create temporary objects
+// in. The empty path stands for the default directory for temporary files,
+// as returned by os.TempDir.
+func tempDirName(parent Path) string {
+	if parent.drive == "" && parent.root == "" && len(parent.parts) == 0 {
+		return ""
+	}
+	return parent.String()
+}
+
+// TempDir creates a new temporary directory in the directory parent on the
+// parent's afero filesystem and returns its path. The name of the directory
+// is generated by appending a random string to prefix. If parent is the empty
+// path, the default directory for temporary files (see os.TempDir) is used. It
+// is the caller's responsibility to remove the directory when it is no longer
+// needed.
+func TempDir(parent Path, prefix string) (Path, error) {
+	name, err := afero.TempDir(parent.Fs(), tempDirName(parent), prefix)
+	if err != nil {
+		return Path{}, parent.pathError("mkdirtemp", err)
+	}
+	return copyPathWithPaths(parent, name), nil
+}
+
+// TempFile creates a new temporary file in the directory parent on the
+// parent's afero filesystem, opens it for reading and writing, and returns
+// the file and its path. The name of the file is generated by taking pattern
+// and appending a random string to the end. If pattern includes a "*", the
+// random string replaces the last "*". It is the caller's responsibility to
+// close and remove the file when it is no longer needed.
+func TempFile(parent Path, pattern string) (*File, Path, error) {
+	file, err := afero.TempFile(parent.Fs(), tempDirName(parent), pattern)
+	if err != nil {
+		return nil, Path{}, parent.pathError("createtemp", err)
+	}
+	return &File{File: file}, copyPathWithPaths(parent, file.Name()), nil
+}
@@ -0,0 +1,42 @@
+package pathlib
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aisbergg/go-pathlib/internal/testutils"
+	"github.com/spf13/afero"
+)
+
+func TestTempDir(t *testing.T) {
+	for _, fs := range []afero.Fs{afero.NewOsFs(), afero.NewMemMapFs()} {
+		assert := testutils.NewAssert(t)
+		require := testutils.NewRequire(t)
+		parent := tempDirForTest(t, fs)
+
+		dir, err := TempDir(parent, "foo")
+		require.NoError(err)
+		assert.Equal(parent, dir.Parent())
+		assert.True(strings.HasPrefix(dir.Name(), "foo"))
+		isDir, err := dir.IsDir()
+		require.NoError(err)
+		assert.True(isDir)
+
+		file, path, err := TempFile(dir, "file*.txt")
+		require.NoError(err)
+		_, err = file.WriteString("hello")
+		require.NoError(err)
+		require.NoError(file.Close())
+		assert.Equal(dir, path.Parent())
+		assert.True(strings.HasPrefix(path.Name(), "file"))
+		assert.Equal(".txt", path.Suffix())
+		content, err := path.ReadFile()
+		require.NoError(err)
+		assert.Equal("hello", string(content))
+
+		_, err = TempDir(parent.Join("i_dont_exist"), "")
+		if _, isMemMapFs := fs.(*afero.MemMapFs); !isMemMapFs {
+			assert.Error(err)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package pathlib
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// procUmask reads the umask from /proc/self/status (Linux 4.7+), which doesn't
+// require changing it temporarily.
+func procUmask() (os.FileMode, bool) {
+	status, err := os.ReadFile("/proc/self/status")
+	if err != nil {
+		return 0, false
+	}
+	for _, line := range strings.Split(string(status), "\n") {
+		if !strings.HasPrefix(line, "Umask:") {
+			continue
+		}
+		mask, err := strconv.ParseUint(strings.TrimSpace(strings.TrimPrefix(line, "Umask:")), 8, 32)
+		if err != nil {
+			return 0, false
+		}
+		return os.FileMode(mask) & os.ModePerm, true
+	}
+	return 0, false
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+// +build !linux
+
+package pathlib
+
+import "os"
+
+// procUmask is not supported on this platform.
+func procUmask() (os.FileMode, bool) {
+	return 0, false
+}
@@ -0,0 +1,157 @@
+package pathlib
+
+import "os"
+
+// blockSize is the unit of ExtendedFileInfo.Blocks.
+const blockSize = 512
+
+// ExtensionUsage is the disk usage of the regular files with a certain
+// extension.
+type ExtensionUsage struct {
+	// Files is the number of files.
+	Files int
+	// ApparentSize is the sum of the file sizes in bytes.
+	ApparentSize int64
+}
+
+// DiskUsageResult is the result of DiskUsage.
+type DiskUsageResult struct {
+	// ApparentSize is the sum of the sizes of all objects in bytes, like
+	// reported by `du --apparent-size --bytes`.
+	ApparentSize int64
+
+	// AllocatedSize is the number of bytes allocated on disk for all objects,
+	// like reported by `du --bytes`. Sparse files take less space than their
+	// apparent size, small files usually more. It is only populated if
+	// AllocatedKnown is true.
+	AllocatedSize int64
+
+	// AllocatedKnown specifies whether the filesystem exposes the number of
+	// allocated blocks.
+	AllocatedKnown bool
+
+	// Files is the number of regular files.
+	Files int
+	// Dirs is the number of directories, including the root directory.
+	Dirs int
+	// Symlinks is the number of symlinks.
+	Symlinks int
+	// Others is the number of objects of other types, e.g. named pipes.
+	Others int
+
+	// Extensions breaks the regular files down by their extension (as
+	// returned by Suffix). Files without an extension are found under the empty
+	// string.
+	Extensions map[string]*ExtensionUsage
+}
+
+// add accounts the object described by info.
+func (r *DiskUsageResult) add(path Path, info os.FileInfo) {
+	r.ApparentSize += info.Size()
+	x := NewExtendedFileInfo(info)
+	if r.AllocatedKnown && x.Has(StatBlocks) {
+		r.AllocatedSize += int64(x.Blocks) * blockSize
+	} else {
+		r.AllocatedKnown = false
+		r.AllocatedSize = 0
+	}
+
+	switch TypeOf(info.Mode()) {
+	case FileTypeRegular:
+		r.Files++
+		ext, ok := r.Extensions[path.Suffix()]
+		if !ok {
+			ext = &ExtensionUsage{}
+			r.Extensions[path.Suffix()] = ext
+		}
+		ext.Files++
+		ext.ApparentSize += info.Size()
+	case FileTypeDir:
+		r.Dirs++
+	case FileTypeSymlink:
+		r.Symlinks++
+	default:
+		r.Others++
+	}
+}
+
+// DiskUsage walks the tree below the path and sums up the sizes of all
+// objects, including the directories themselves, like `du -s` does. Symlinks
+// are not followed. Files with multiple hard links within the tree are
+// accounted only once.
+func (p Path) DiskUsage() (*DiskUsageResult, error) {
+	result := &DiskUsageResult{
+		AllocatedKnown: true,
+		Extensions:     map[string]*ExtensionUsage{},
+	}
+	seen := map[FileID]struct{}{}
+	visit := func(path Path, info os.FileInfo) {
+		if !info.IsDir() {
+			if x := NewExtendedFileInfo(info); x.Has(StatNlink) && x.Nlink > 1 {
+				if id, ok := fileIDFromInfo(info); ok {
+					if _, found := seen[id]; found {
+						return
+					}
+					seen[id] = struct{}{}
+				}
+			}
+		}
+		result.add(path, info)
+	}
+
+	info, err := p.lstatIfPossible()
+	if err != nil {
+		return nil, err
+	}
+	visit(p, info)
+	if !info.IsDir() {
+		return result, nil
+	}
+
+	walk, err := NewWalk(p)
+	if err != nil {
+		return nil, err
+	}
+	if err := walk.Walk(func(path Path, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		visit(path, info)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// -----------------------------------------------------------------------------
+//
+// Free space
+//
+// -----------------------------------------------------------------------------
+
+// DiskSpace describes the capacity of a filesystem in bytes.
+type DiskSpace struct {
+	// Total is the size of the filesystem.
+	Total uint64
+	// Free is the amount of free space, including the space reserved for the
+	// superuser.
+	Free uint64
+	// Available is the amount of free space available to unprivileged users.
+	Available uint64
+}
+
+// FreeSpace returns the capacity of the filesystem the path resides on, like
+// `df` does.
+//
+// This will fail if the underlying afero filesystem is not OsFs.
+func (p Path) FreeSpace() (*DiskSpace, error) {
+	if !isOsFs(p.Fs()) {
+		return nil, p.pathError("statfs", p.doesNotImplementErr("FreeSpace"))
+	}
+	space, err := diskSpace(p.String())
+	if err != nil {
+		return nil, p.pathError("statfs", err)
+	}
+	return space, nil
+}
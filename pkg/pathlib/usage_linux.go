@@ -0,0 +1,23 @@
+package pathlib
+
+import (
+	"os"
+	"syscall"
+)
+
+func diskSpace(path string) (*DiskSpace, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return nil, &os.PathError{Op: "statfs", Path: path, Err: err}
+	}
+	// the block counts are given in units of the fragment size, if provided
+	size := uint64(stat.Frsize)
+	if size == 0 {
+		size = uint64(stat.Bsize)
+	}
+	return &DiskSpace{
+		Total:     uint64(stat.Blocks) * size,
+		Free:      uint64(stat.Bfree) * size,
+		Available: uint64(stat.Bavail) * size,
+	}, nil
+}
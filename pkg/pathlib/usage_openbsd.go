@@ -0,0 +1,24 @@
+package pathlib
+
+import (
+	"os"
+	"syscall"
+)
+
+func diskSpace(path string) (*DiskSpace, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return nil, &os.PathError{Op: "statfs", Path: path, Err: err}
+	}
+	size := uint64(stat.F_bsize)
+	avail := int64(stat.F_bavail)
+	if avail < 0 {
+		// reported as negative if the reserved space is in use
+		avail = 0
+	}
+	return &DiskSpace{
+		Total:     uint64(stat.F_blocks) * size,
+		Free:      uint64(stat.F_bfree) * size,
+		Available: uint64(avail) * size,
+	}, nil
+}
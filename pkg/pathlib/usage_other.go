@@ -0,0 +1,13 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !openbsd && !windows
+// +build !darwin,!dragonfly,!freebsd,!linux,!openbsd,!windows
+
+package pathlib
+
+import (
+	"os"
+	"syscall"
+)
+
+func diskSpace(path string) (*DiskSpace, error) {
+	return nil, &os.PathError{Op: "statfs", Path: path, Err: syscall.ENOTSUP}
+}
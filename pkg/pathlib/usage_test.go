@@ -0,0 +1,79 @@
+package pathlib
+
+import (
+	"errors"
+	"io/fs"
+	"runtime"
+	"testing"
+
+	"github.com/aisbergg/go-pathlib/internal/testutils"
+	"github.com/spf13/afero"
+)
+
+func TestDiskUsage(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	require.NoError(tmpdir.Join("a.txt").WriteFile([]byte("hello")))
+	require.NoError(tmpdir.Join("subdir").Mkdir())
+	require.NoError(tmpdir.Join("subdir", "b.txt").WriteFile([]byte("hello world")))
+	require.NoError(tmpdir.Join("subdir", "main.go").WriteFile([]byte("package main")))
+	require.NoError(tmpdir.Join("README").WriteFile([]byte("readme")))
+	require.NoError(tmpdir.Join("link").Symlink(tmpdir.Join("a.txt")))
+	require.NoError(tmpdir.Join("subdir", "hardlink.txt").HardLinkTo(tmpdir.Join("a.txt")))
+
+	usage, err := tmpdir.DiskUsage()
+	require.NoError(err)
+	assert.Equal(4, usage.Files)
+	assert.Equal(2, usage.Dirs)
+	assert.Equal(1, usage.Symlinks)
+	assert.Equal(0, usage.Others)
+	assert.Equal(3, len(usage.Extensions))
+	assert.Equal(&ExtensionUsage{Files: 2, ApparentSize: 16}, usage.Extensions[".txt"])
+	assert.Equal(&ExtensionUsage{Files: 1, ApparentSize: 12}, usage.Extensions[".go"])
+	assert.Equal(&ExtensionUsage{Files: 1, ApparentSize: 6}, usage.Extensions[""])
+	assert.True(usage.ApparentSize >= 34)
+	if runtime.GOOS != "windows" {
+		assert.True(usage.AllocatedKnown)
+		assert.True(usage.AllocatedSize > 0)
+	}
+
+	usage, err = tmpdir.Join("subdir", "b.txt").DiskUsage()
+	require.NoError(err)
+	assert.Equal(1, usage.Files)
+	assert.Equal(int64(11), usage.ApparentSize)
+
+	_, err = tmpdir.Join("i_dont_exist").DiskUsage()
+	assert.True(errors.Is(err, fs.ErrNotExist))
+}
+
+func TestDiskUsageMemMapFs(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	root := NewPathWithFS(afero.NewMemMapFs(), "/root")
+	require.NoError(root.MkdirAll())
+	require.NoError(TwoFilesAtRootTwoInSubdir(root))
+
+	usage, err := root.DiskUsage()
+	require.NoError(err)
+	assert.Equal(4, usage.Files)
+	assert.Equal(2, usage.Dirs)
+	assert.False(usage.AllocatedKnown)
+	assert.Equal(int64(0), usage.AllocatedSize)
+}
+
+func TestFreeSpace(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+
+	space, err := tmpdir.FreeSpace()
+	require.NoError(err)
+	assert.True(space.Total > 0)
+	assert.True(space.Free <= space.Total)
+	assert.True(space.Available <= space.Free)
+
+	_, err = tmpdir.Join("i_dont_exist").FreeSpace()
+	assert.True(errors.Is(err, fs.ErrNotExist))
+
+	_, err = NewPathWithFS(afero.NewMemMapFs(), "/").FreeSpace()
+	assert.EqualError(ErrDoesNotImplement, err)
+}
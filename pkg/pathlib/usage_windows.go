@@ -0,0 +1,27 @@
+package pathlib
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var procGetDiskFreeSpaceExW = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+
+func diskSpace(path string) (*DiskSpace, error) {
+	pathPtr, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, &os.PathError{Op: "GetDiskFreeSpaceEx", Path: path, Err: err}
+	}
+	var space DiskSpace
+	r, _, err := procGetDiskFreeSpaceExW.Call(
+		uintptr(unsafe.Pointer(pathPtr)),
+		uintptr(unsafe.Pointer(&space.Available)),
+		uintptr(unsafe.Pointer(&space.Total)),
+		uintptr(unsafe.Pointer(&space.Free)),
+	)
+	if r == 0 {
+		return nil, &os.PathError{Op: "GetDiskFreeSpaceEx", Path: path, Err: err}
+	}
+	return &space, nil
+}
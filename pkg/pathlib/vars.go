@@ -1,9 +1,23 @@
 package pathlib
 
-import "os"
+import (
+	"os"
+	"time"
+)
 
 // DefaultFileMode is the file mode that will be applied to new pathlib files
 var DefaultFileMode = os.FileMode(0o644)
 
 // DefaultDirMode is the default mode that will be applied to new directories
 var DefaultDirMode = os.FileMode(0o755)
+
+// MaxSymlinkHops is the maximum number of symlinks that are followed while
+// resolving a single path, like ELOOP of the Linux kernel
+var MaxSymlinkHops = 40
+
+// LockPollInterval is the interval in which LockContext retries to acquire an
+// OS file lock
+var LockPollInterval = 10 * time.Millisecond
+
+// DirBatchSize is the number of directory entries ReadDirIter reads at once
+var DirBatchSize = 1024
@@ -1,6 +1,7 @@
 package pathlib
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -40,6 +41,17 @@ type WalkOpts struct {
 	// VisitSymlinks specifies that we should visit symlinks during the walk.
 	VisitSymlinks bool
 
+	// VisitFIFOs specifies that we should visit named pipes during the walk.
+	VisitFIFOs bool
+
+	// VisitSockets specifies that we should visit Unix domain sockets during
+	// the walk.
+	VisitSockets bool
+
+	// VisitDevices specifies that we should visit block and character devices
+	// during the walk.
+	VisitDevices bool
+
 	// VisitFirst specifies that, in the algorithms where it is appropriate,
 	// a node's contents should be visited first, before recursing down. If false,
 	// a node's subdirectories will be recursed first before visiting any of its
@@ -62,6 +74,9 @@ func DefaultWalkOpts() *WalkOpts {
 		VisitFiles:      true,
 		VisitDirs:       true,
 		VisitSymlinks:   true,
+		VisitFIFOs:      true,
+		VisitSockets:    true,
+		VisitDevices:    true,
 	}
 }
 
@@ -110,7 +125,7 @@ func NewWalk(root Path) (*Walk, error) {
 // NewWalkWithOpts returns a Walk object with the given WalkOpts applied
 func NewWalkWithOpts(root Path, opts *WalkOpts) (*Walk, error) {
 	if opts == nil {
-		return nil, fmt.Errorf("opts can't be nil")
+		return nil, root.pathError("walk", fmt.Errorf("opts can't be nil"))
 	}
 	return &Walk{
 		Opts: opts,
@@ -131,18 +146,18 @@ type dfsObjectInfo struct {
 	err  error
 }
 
-func (w *Walk) walkDFS(walkFn WalkFunc, root Path, currentDepth int) error {
+func (w *Walk) walkDFS(ctx context.Context, walkFn WalkFunc, root Path, currentDepth int) error {
 	if w.maxDepthReached(currentDepth) {
 		return nil
 	}
 
 	var children []*dfsObjectInfo
 
-	if err := w.iterateImmediateChildren(root, func(child Path, info os.FileInfo, encounteredErr error) error {
+	if err := w.iterateImmediateChildren(ctx, root, func(child Path, info os.FileInfo, encounteredErr error) error {
 		// Since we are doing depth-first, we have to first recurse through all the directories,
 		// and save all non-directory objects so we can defer handling at a later time.
 		if IsDir(info.Mode()) {
-			if err := w.walkDFS(walkFn, child, currentDepth+1); err != nil {
+			if err := w.walkDFS(ctx, walkFn, child, currentDepth+1); err != nil {
 				return err
 			}
 		}
@@ -179,24 +194,38 @@ func (w *Walk) walkDFS(walkFn WalkFunc, root Path, currentDepth int) error {
 // and will run the algorithm function for every child. The algorithm function is essentially
 // what differentiates how each walk behaves, and determines what actions to take given a
 // certain child.
-func (w *Walk) iterateImmediateChildren(root Path, algorithmFunction WalkFunc) error {
-	children, err := root.ReadDir()
+func (w *Walk) iterateImmediateChildren(ctx context.Context, root Path, algorithmFunction WalkFunc) error {
+	entries, err := root.Entries()
 	if err != nil {
 		return err
 	}
 
-	var info os.FileInfo
-	for _, child := range children {
+	for _, entry := range entries {
+		child := entry.Path()
 		if child.String() == root.String() {
 			continue
 		}
-		if w.Opts.FollowSymlinks {
+		if err := ctx.Err(); err != nil {
+			return child.pathError("walk", err)
+		}
+		typ := entry.Type()
+		follow := w.Opts.FollowSymlinks && IsSymlink(typ)
+		if !follow && !IsDir(typ) && !w.visitsType(typ) {
+			// the object is neither visited nor descended into, so its type
+			// from the directory listing suffices to skip it
+			continue
+		}
+
+		var info os.FileInfo
+		if follow {
 			info, err = child.Stat()
 			if err != nil {
 				return err
 			}
 		} else {
-			info, err = child.Lstat()
+			// filesystems without lstat support have no symlinks, so the
+			// entry's information is equivalent to stat-ing there
+			info, err = entry.Info()
 		}
 
 		if info == nil {
@@ -217,32 +246,47 @@ func (w *Walk) iterateImmediateChildren(root Path, algorithmFunction WalkFunc) e
 // the os.FileInfo passes all of the query specifications listed in
 // the walk options.
 func (w *Walk) passesQuerySpecification(info os.FileInfo) (bool, error) {
+	if !w.visitsType(info.Mode()) {
+		return false, nil
+	}
 	if IsFile(info.Mode()) {
-		if !w.Opts.VisitFiles {
-			return false, nil
-		}
-
 		if !w.Opts.MeetsMinimumSize(info.Size()) ||
 			!w.Opts.MeetsMaximumSize(info.Size()) {
 			return false, nil
 		}
-	} else if IsDir(info.Mode()) && !w.Opts.VisitDirs {
-		return false, nil
-	} else if IsSymlink(info.Mode()) && !w.Opts.VisitSymlinks {
-		return false, nil
 	}
 
 	return true, nil
 }
 
-func (w *Walk) walkBasic(walkFn WalkFunc, root Path, currentDepth int) error {
+// visitsType returns whether objects of the type given by the mode are
+// visited according to the Visit* options of the walk.
+func (w *Walk) visitsType(mode os.FileMode) bool {
+	switch {
+	case IsFile(mode):
+		return w.Opts.VisitFiles
+	case IsDir(mode):
+		return w.Opts.VisitDirs
+	case IsSymlink(mode):
+		return w.Opts.VisitSymlinks
+	case IsFIFO(mode):
+		return w.Opts.VisitFIFOs
+	case IsSocket(mode):
+		return w.Opts.VisitSockets
+	case mode&os.ModeDevice != 0:
+		return w.Opts.VisitDevices
+	}
+	return true
+}
+
+func (w *Walk) walkBasic(ctx context.Context, walkFn WalkFunc, root Path, currentDepth int) error {
 	if w.maxDepthReached(currentDepth) {
 		return nil
 	}
 
-	err := w.iterateImmediateChildren(root, func(child Path, info os.FileInfo, encounteredErr error) error {
+	err := w.iterateImmediateChildren(ctx, root, func(child Path, info os.FileInfo, encounteredErr error) error {
 		if IsDir(info.Mode()) {
-			if err := w.walkBasic(walkFn, child, currentDepth+1); err != nil {
+			if err := w.walkBasic(ctx, walkFn, child, currentDepth+1); err != nil {
 				return err
 			}
 		}
@@ -269,10 +313,16 @@ type WalkFunc func(path Path, info os.FileInfo, err error) error
 
 // Walk walks the directory using the algorithm specified in the configuration.
 func (w *Walk) Walk(walkFn WalkFunc) error {
+	return w.WalkContext(context.Background(), walkFn)
+}
 
+// WalkContext is like Walk but stops once the context is done. The context is
+// checked before each directory entry is visited; the returned error wraps
+// ctx.Err() with the path of the entry.
+func (w *Walk) WalkContext(ctx context.Context, walkFn WalkFunc) error {
 	switch w.Opts.Algorithm {
 	case AlgorithmBasic:
-		if err := w.walkBasic(walkFn, w.root, 0); err != nil {
+		if err := w.walkBasic(ctx, walkFn, w.root, 0); err != nil {
 			if errors.Is(err, ErrStopWalk) {
 				return nil
 			}
@@ -280,7 +330,7 @@ func (w *Walk) Walk(walkFn WalkFunc) error {
 		}
 		return nil
 	case AlgorithmDepthFirst:
-		if err := w.walkDFS(walkFn, w.root, 0); err != nil {
+		if err := w.walkDFS(ctx, walkFn, w.root, 0); err != nil {
 			if errors.Is(err, ErrStopWalk) {
 				return nil
 			}
@@ -2,9 +2,9 @@ package pathlib
 
 import (
 	"fmt"
-	"io/ioutil"
 	"os"
 	"reflect"
+	"sort"
 	"testing"
 
 	"github.com/aisbergg/go-pathlib/internal/testutils"
@@ -20,12 +20,10 @@ var algorithms = []struct {
 }
 
 func setupWalkTest(t *testing.T, algorithm Algorithm) *Walk {
-	tmpdir, err := ioutil.TempDir("", "")
+	root, err := TempDir(NewPath(), "")
 	if err != nil {
 		t.FailNow()
 	}
-	fs := afero.NewOsFs()
-	root := NewPathWithFS(fs, tmpdir)
 	walk, err := NewWalk(root)
 	if err != nil {
 		t.FailNow()
@@ -119,6 +117,21 @@ func TestZeroDepth(t *testing.T) {
 	}
 }
 
+func TestWalkMemMapFs(t *testing.T) {
+	require := testutils.NewRequire(t)
+	for _, a := range algorithms {
+		t.Run(a.name, func(t *testing.T) {
+			root := NewPathWithFS(afero.NewMemMapFs(), "/root")
+			require.NoError(root.MkdirAll())
+			w, err := NewWalk(root)
+			require.NoError(err)
+			w.Opts.Algorithm = a.alg
+			require.NoError(TwoFilesAtRootTwoInSubdir(w.root))
+			testWalkScenario(t, w, 5, nil, nil)
+		})
+	}
+}
+
 func TestStopWalk(t *testing.T) {
 	require := testutils.NewRequire(t)
 	tf := func(t *testing.T, alg Algorithm) {
@@ -154,6 +167,40 @@ func TestWalkFuncErr(t *testing.T) {
 	}
 }
 
+func TestWalkSkipsByType(t *testing.T) {
+	assert, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	require.NoError(TwoFilesAtRootTwoInSubdir(tmpdir))
+	require.NoError(tmpdir.Join("link").Symlink(tmpdir.Join("subdir")))
+	counting := &infoCountingFs{Fs: afero.NewOsFs()}
+	root := NewPathWithFS(counting, tmpdir.String())
+
+	walk, err := NewWalk(root)
+	require.NoError(err)
+	walk.Opts.VisitFiles = false
+	var visited []string
+	require.NoError(walk.Walk(func(path Path, info os.FileInfo, err error) error {
+		visited = append(visited, path.Name())
+		return err
+	}))
+	sort.Strings(visited)
+	assert.Equal([]string{"link", "subdir"}, visited)
+	// skipped files need no file information
+	assert.Equal(int64(2), counting.infos)
+
+	counting.infos = 0
+	walk.Opts.FollowSymlinks = true
+	visited = nil
+	require.NoError(walk.Walk(func(path Path, info os.FileInfo, err error) error {
+		visited = append(visited, path.Name())
+		return err
+	}))
+	sort.Strings(visited)
+	assert.Equal([]string{"link", "subdir"}, visited)
+	// followed symlinks are stat'ed right away
+	assert.Equal(int64(1), counting.infos)
+}
+
 func TestPassesQuerySpecification(t *testing.T) {
 	assert := testutils.NewAssert(t)
 	require := testutils.NewRequire(t)
@@ -245,6 +292,9 @@ func TestDefaultWalkOpts(t *testing.T) {
 			VisitFiles:      true,
 			VisitDirs:       true,
 			VisitSymlinks:   true,
+			VisitFIFOs:      true,
+			VisitSockets:    true,
+			VisitDevices:    true,
 		}},
 	}
 	for _, tt := range tests {
@@ -0,0 +1,413 @@
+package pathlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// WatchOp is a bit set of the kinds of changes reported by Watch.
+type WatchOp uint32
+
+const (
+	// WatchCreate reports that an object was created or moved into the watched
+	// tree.
+	WatchCreate WatchOp = 1 << iota
+	// WatchModify reports that the content of a file was modified.
+	WatchModify
+	// WatchRemove reports that an object was removed.
+	WatchRemove
+	// WatchRename reports that an object was renamed or moved away. If the new
+	// name is within the watched tree, a WatchCreate event is reported for it
+	// as well.
+	WatchRename
+)
+
+// String returns the names of the operations in the set, like
+// "CREATE|MODIFY".
+func (op WatchOp) String() string {
+	var names []string
+	for _, o := range []struct {
+		op   WatchOp
+		name string
+	}{
+		{WatchCreate, "CREATE"},
+		{WatchModify, "MODIFY"},
+		{WatchRemove, "REMOVE"},
+		{WatchRename, "RENAME"},
+	} {
+		if op&o.op != 0 {
+			names = append(names, o.name)
+		}
+	}
+	return strings.Join(names, "|")
+}
+
+// WatchEvent is a change reported by Watch.
+type WatchEvent struct {
+	// Path is the path of the changed object.
+	Path Path
+	// Op is the set of changes. It contains more than one operation if
+	// multiple changes have been coalesced.
+	Op WatchOp
+	// Err is set if watching failed. It is the last event sent before the
+	// channel is closed.
+	Err error
+}
+
+// String returns a human readable representation of the event.
+func (e WatchEvent) String() string {
+	if e.Err != nil {
+		return fmt.Sprintf("error: %v", e.Err)
+	}
+	return fmt.Sprintf("%s %s", e.Op, e.Path)
+}
+
+// WatchOpts is the struct that defines how a tree should be watched.
+type WatchOpts struct {
+	// Recursive specifies that the whole tree below the path is watched. If
+	// false, only the direct children of the path are watched.
+	Recursive bool
+
+	// Debounce is the time window in which events are coalesced. All events of
+	// a path that occur within the window are reported as a single event with
+	// the operations combined. A value of 0 reports every event immediately.
+	Debounce time.Duration
+
+	// PollInterval specifies how often the tree is scanned for changes by the
+	// polling implementation. A value of 0 or less stands for the interval of
+	// DefaultWatchOpts.
+	PollInterval time.Duration
+
+	// Polling forces the polling implementation, even where a native
+	// notification mechanism is available.
+	Polling bool
+
+	// Filter specifies which objects are reported. The Visit*, size and
+	// FollowSymlinks options are applied to each event, the Depth option
+	// limits the recursion. The objects of remove and rename events are
+	// filtered by their last known state, which only tells directories apart
+	// from other objects for the native implementation.
+	Filter *WalkOpts
+}
+
+// DefaultWatchOpts returns the default WatchOpts struct used when watching a
+// tree.
+func DefaultWatchOpts() *WatchOpts {
+	return &WatchOpts{
+		Recursive:    true,
+		Debounce:     0,
+		PollInterval: time.Second,
+		Polling:      false,
+		Filter:       DefaultWalkOpts(),
+	}
+}
+
+// Watch watches the directory for changes and sends them on the returned
+// channel until the context is cancelled, after which the channel is closed.
+// The receiver must drain the channel to keep the watch going.
+//
+// For OsFs on Linux, inotify is used. Any other afero filesystem is watched by
+// periodically walking the tree and comparing the results, which means that
+// changes in between two scans are only visible by their net effect.
+func (p Path) Watch(ctx context.Context, opts *WatchOpts) (<-chan WatchEvent, error) {
+	if opts == nil {
+		return nil, p.pathError("watch", fmt.Errorf("opts can't be nil"))
+	}
+	info, err := p.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, p.pathError("watch", syscall.ENOTDIR)
+	}
+
+	watchOpts := *opts
+	if watchOpts.PollInterval <= 0 {
+		watchOpts.PollInterval = DefaultWatchOpts().PollInterval
+	}
+	if watchOpts.Filter == nil {
+		watchOpts.Filter = DefaultWalkOpts()
+	}
+	walkOpts := *watchOpts.Filter
+	if !opts.Recursive {
+		walkOpts.Depth = 0
+	}
+	walk, err := NewWalkWithOpts(p, &walkOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make(chan WatchEvent)
+	w := &watcher{
+		walk:   walk,
+		opts:   &watchOpts,
+		events: raw,
+	}
+	var run func(ctx context.Context)
+	if !opts.Polling && isOsFs(p.Fs()) {
+		run, err = w.native()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if run == nil {
+		run, err = w.polling()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	events := make(chan WatchEvent)
+	go func() {
+		defer close(raw)
+		run(ctx)
+	}()
+	go coalesce(ctx, raw, events, opts.Debounce)
+	return events, nil
+}
+
+// watcher holds the state shared by the Watch implementations.
+type watcher struct {
+	walk   *Walk
+	opts   *WatchOpts
+	events chan<- WatchEvent
+}
+
+// root returns the watched path.
+func (w *watcher) root() Path {
+	return w.walk.root
+}
+
+// send sends the event unless the context is done. It returns false if the
+// watch should stop.
+func (w *watcher) send(ctx context.Context, event WatchEvent) bool {
+	select {
+	case w.events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// passes returns whether the object described by info passes the filter.
+func (w *watcher) passes(info os.FileInfo) bool {
+	passes, _ := w.walk.passesQuerySpecification(info)
+	return passes
+}
+
+// stat returns the information of the path the filter is applied to.
+func (w *watcher) stat(path Path) (os.FileInfo, error) {
+	if w.walk.Opts.FollowSymlinks {
+		return path.Stat()
+	}
+	return path.lstatIfPossible()
+}
+
+// -----------------------------------------------------------------------------
+//
+// Polling
+//
+// -----------------------------------------------------------------------------
+
+// watchEntry is the state of an object recorded in a watchSnapshot. Size and
+// modification time are copied, as some afero filesystems return live views of
+// the object.
+type watchEntry struct {
+	info    os.FileInfo
+	size    int64
+	modTime time.Time
+}
+
+// watchSnapshot maps the paths of a tree to their state.
+type watchSnapshot map[string]watchEntry
+
+// polling returns the polling implementation of the watcher. The initial
+// snapshot is taken before it returns.
+func (w *watcher) polling() (func(ctx context.Context), error) {
+	prev, err := w.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(w.opts.PollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			next, err := w.snapshot()
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					// objects vanished during the scan, try again next time
+					continue
+				}
+				w.send(ctx, WatchEvent{Err: err})
+				return
+			}
+			for _, event := range w.diff(prev, next) {
+				if !w.send(ctx, event) {
+					return
+				}
+			}
+			prev = next
+		}
+	}, nil
+}
+
+// snapshot walks the tree and records the information of all objects. A
+// missing root results in an empty snapshot.
+func (w *watcher) snapshot() (watchSnapshot, error) {
+	snapshot := watchSnapshot{}
+	err := w.walk.Walk(func(path Path, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		snapshot[path.String()] = watchEntry{
+			info:    info,
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		}
+		return nil
+	})
+	if err != nil && errors.Is(err, fs.ErrNotExist) {
+		if exists, eerr := w.root().Exists(); eerr == nil && !exists {
+			return snapshot, nil
+		}
+	}
+	return snapshot, err
+}
+
+// diff returns the events that turn the snapshot prev into next, sorted by
+// path.
+func (w *watcher) diff(prev, next watchSnapshot) []WatchEvent {
+	var created, removed []string
+	ops := map[string]WatchOp{}
+	for name, entry := range next {
+		old, found := prev[name]
+		switch {
+		case !found:
+			created = append(created, name)
+			ops[name] = WatchCreate
+		case !entry.info.IsDir() && (!entry.modTime.Equal(old.modTime) || entry.size != old.size):
+			ops[name] = WatchModify
+		}
+	}
+	for name := range prev {
+		if _, found := next[name]; !found {
+			removed = append(removed, name)
+			ops[name] = WatchRemove
+		}
+	}
+
+	// objects that reappear under a different name have been renamed
+	createdByID := map[FileID]string{}
+	for _, name := range created {
+		if id, ok := fileIDFromInfo(next[name].info); ok {
+			createdByID[id] = name
+		}
+	}
+	for _, name := range removed {
+		if id, ok := fileIDFromInfo(prev[name].info); ok {
+			if _, found := createdByID[id]; found {
+				ops[name] = WatchRename
+			}
+		}
+	}
+
+	names := make([]string, 0, len(ops))
+	for name := range ops {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	events := make([]WatchEvent, 0, len(names))
+	for _, name := range names {
+		entry, found := next[name]
+		if !found {
+			entry = prev[name]
+		}
+		if !w.passes(entry.info) {
+			continue
+		}
+		events = append(events, WatchEvent{
+			Path: copyPathWithPaths(w.root(), name),
+			Op:   ops[name],
+		})
+	}
+	return events
+}
+
+// -----------------------------------------------------------------------------
+//
+// Coalescing
+//
+// -----------------------------------------------------------------------------
+
+// coalesce forwards the events from in to out, combining the events of a path
+// that occur within the given window. It closes out once in is closed.
+func coalesce(ctx context.Context, in <-chan WatchEvent, out chan<- WatchEvent, window time.Duration) {
+	defer close(out)
+	send := func(event WatchEvent) {
+		select {
+		case out <- event:
+		case <-ctx.Done():
+		}
+	}
+
+	var pending []WatchEvent
+	index := map[string]int{}
+	flush := func() {
+		for _, event := range pending {
+			send(event)
+		}
+		pending = nil
+		index = map[string]int{}
+	}
+
+	var timer *time.Timer
+	var timeout <-chan time.Time
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+	for {
+		select {
+		case event, ok := <-in:
+			if !ok {
+				flush()
+				return
+			}
+			if window <= 0 {
+				send(event)
+				continue
+			}
+			if event.Err != nil {
+				flush()
+				send(event)
+				continue
+			}
+			if i, found := index[event.Path.String()]; found {
+				pending[i].Op |= event.Op
+				continue
+			}
+			index[event.Path.String()] = len(pending)
+			pending = append(pending, event)
+			if timeout == nil {
+				timer = time.NewTimer(window)
+				timeout = timer.C
+			}
+		case <-timeout:
+			flush()
+			timer, timeout = nil, nil
+		}
+	}
+}
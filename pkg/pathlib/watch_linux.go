@@ -0,0 +1,261 @@
+package pathlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// inotifyMask is the set of inotify events that are watched for.
+const inotifyMask = syscall.IN_CREATE | syscall.IN_MODIFY | syscall.IN_DELETE |
+	syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO | syscall.IN_ONLYDIR
+
+// inotifyDir is a directory watched by inotify.
+type inotifyDir struct {
+	path  string
+	depth int
+}
+
+// inotifyWatcher is the inotify implementation of the watcher.
+type inotifyWatcher struct {
+	*watcher
+	file  *os.File
+	conn  syscall.RawConn
+	dirs  map[int]inotifyDir
+	paths map[string]int
+}
+
+// native returns the inotify implementation of the watcher. The watches are
+// established before it returns.
+func (w *watcher) native() (func(ctx context.Context), error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, os.NewSyscallError("inotify_init1", err)
+	}
+	// a non-blocking file is registered with the runtime poller, so a pending
+	// Read returns once the file is closed
+	iw := &inotifyWatcher{
+		watcher: w,
+		file:    os.NewFile(uintptr(fd), "inotify"),
+		dirs:    map[int]inotifyDir{},
+		paths:   map[string]int{},
+	}
+	// the descriptor is only used through the raw connection, which keeps it
+	// from being closed, and maybe reused, during a call
+	if iw.conn, err = iw.file.SyscallConn(); err != nil {
+		iw.file.Close()
+		return nil, err
+	}
+	if _, err := iw.addTree(w.root().String(), -1); err != nil {
+		iw.file.Close()
+		return nil, err
+	}
+	return iw.run, nil
+}
+
+// addTree watches the directory and, within the depth limit, its
+// subdirectories. depth is the walk depth of the directory itself, -1 for the
+// root. It returns the paths of the objects found below the directory.
+func (w *inotifyWatcher) addTree(dir string, depth int) ([]string, error) {
+	var (
+		wd  int
+		err error
+	)
+	cerr := w.conn.Control(func(fd uintptr) {
+		wd, err = syscall.InotifyAddWatch(int(fd), dir, inotifyMask)
+	})
+	if cerr != nil {
+		// the file has been closed, the watch is stopping
+		return nil, &os.PathError{Op: "inotify_add_watch", Path: dir, Err: os.ErrClosed}
+	}
+	if err != nil {
+		return nil, &os.PathError{Op: "inotify_add_watch", Path: dir, Err: err}
+	}
+	w.dirs[wd] = inotifyDir{path: dir, depth: depth}
+	w.paths[dir] = wd
+
+	entries, err := copyPathWithPaths(w.root(), dir).ReadDir()
+	if err != nil {
+		return nil, err
+	}
+	var found []string
+	for _, entry := range entries {
+		found = append(found, entry.String())
+		info, err := w.stat(entry)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		// the children of the entry are two levels below the directory
+		if info.IsDir() && !w.walk.maxDepthReached(depth+2) {
+			sub, err := w.addTree(entry.String(), depth+1)
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					continue
+				}
+				return nil, err
+			}
+			found = append(found, sub...)
+		}
+	}
+	return found, nil
+}
+
+// removeTree stops watching the directory and its subdirectories.
+func (w *inotifyWatcher) removeTree(dir string) {
+	prefix := dir + string(filepath.Separator)
+	for path, wd := range w.paths {
+		if path == dir || strings.HasPrefix(path, prefix) {
+			w.conn.Control(func(fd uintptr) {
+				syscall.InotifyRmWatch(int(fd), uint32(wd))
+			})
+			delete(w.paths, path)
+			delete(w.dirs, wd)
+		}
+	}
+}
+
+// run reads and translates the inotify events until the context is done.
+func (w *inotifyWatcher) run(ctx context.Context) {
+	// the file is closed exactly once, when the context is done or run returns
+	// on its own, whichever comes first
+	ctx, cancel := context.WithCancel(ctx)
+	closed := make(chan struct{})
+	defer func() {
+		cancel()
+		<-closed
+	}()
+	go func() {
+		defer close(closed)
+		<-ctx.Done()
+		w.file.Close()
+	}()
+
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+	for {
+		n, err := w.file.Read(buf)
+		if err != nil {
+			if ctx.Err() == nil && !errors.Is(err, os.ErrClosed) {
+				w.send(ctx, WatchEvent{Err: err})
+			}
+			return
+		}
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+			raw := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			nameBytes := buf[offset+syscall.SizeofInotifyEvent : offset+syscall.SizeofInotifyEvent+int(raw.Len)]
+			offset += syscall.SizeofInotifyEvent + int(raw.Len)
+
+			if raw.Mask&syscall.IN_Q_OVERFLOW != 0 {
+				w.send(ctx, WatchEvent{Err: fmt.Errorf("inotify event queue overflowed")})
+				return
+			}
+			if !w.handle(ctx, int(raw.Wd), raw.Mask, cString(nameBytes)) {
+				return
+			}
+		}
+	}
+}
+
+// handle translates a single inotify event. It returns false if the watch
+// should stop.
+func (w *inotifyWatcher) handle(ctx context.Context, wd int, mask uint32, name string) bool {
+	dir, found := w.dirs[wd]
+	if !found {
+		return true
+	}
+	if mask&syscall.IN_IGNORED != 0 {
+		delete(w.dirs, wd)
+		delete(w.paths, dir.path)
+		return true
+	}
+	if name == "" {
+		return true
+	}
+	path := filepath.Join(dir.path, name)
+	isDir := mask&syscall.IN_ISDIR != 0
+
+	var op WatchOp
+	switch {
+	case mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0:
+		op = WatchCreate
+	case mask&syscall.IN_MODIFY != 0:
+		op = WatchModify
+	case mask&syscall.IN_DELETE != 0:
+		op = WatchRemove
+	case mask&syscall.IN_MOVED_FROM != 0:
+		op = WatchRename
+	default:
+		return true
+	}
+
+	if op&(WatchRemove|WatchRename) != 0 {
+		if isDir {
+			w.removeTree(path)
+		}
+		if !w.passesGone(isDir) {
+			return true
+		}
+		return w.send(ctx, WatchEvent{Path: copyPathWithPaths(w.root(), path), Op: op})
+	}
+
+	var created []string
+	info, err := w.stat(copyPathWithPaths(w.root(), path))
+	if err != nil {
+		// gone again already, the object can only be filtered by the event
+		if !w.passesGone(isDir) {
+			return true
+		}
+		return w.send(ctx, WatchEvent{Path: copyPathWithPaths(w.root(), path), Op: op})
+	}
+	if op == WatchCreate && info.IsDir() && !w.walk.maxDepthReached(dir.depth+2) {
+		// objects created before the watch was established would be missed
+		created, err = w.addTree(path, dir.depth+1)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			w.send(ctx, WatchEvent{Err: err})
+			return false
+		}
+	}
+	if w.passes(info) {
+		if !w.send(ctx, WatchEvent{Path: copyPathWithPaths(w.root(), path), Op: op}) {
+			return false
+		}
+	}
+	for _, name := range created {
+		info, err := w.stat(copyPathWithPaths(w.root(), name))
+		if err != nil || !w.passes(info) {
+			continue
+		}
+		if !w.send(ctx, WatchEvent{Path: copyPathWithPaths(w.root(), name), Op: WatchCreate}) {
+			return false
+		}
+	}
+	return true
+}
+
+// passesGone returns whether an object that doesn't exist anymore passes the
+// filter. Only directories can be told apart from other objects.
+func (w *inotifyWatcher) passesGone(isDir bool) bool {
+	opts := w.walk.Opts
+	if isDir {
+		return opts.VisitDirs
+	}
+	return opts.VisitFiles || opts.VisitSymlinks || opts.VisitFIFOs || opts.VisitSockets || opts.VisitDevices
+}
+
+// cString returns the string in the NUL-padded buffer.
+func cString(b []byte) string {
+	for i, c := range b {
+		if c == 0 {
+			return string(b[:i])
+		}
+	}
+	return string(b)
+}
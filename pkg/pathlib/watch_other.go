@@ -0,0 +1,12 @@
+//go:build !linux
+// +build !linux
+
+package pathlib
+
+import "context"
+
+// native is not supported on this platform, the polling implementation is
+// used instead.
+func (w *watcher) native() (func(ctx context.Context), error) {
+	return nil, nil
+}
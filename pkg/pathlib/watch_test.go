@@ -0,0 +1,191 @@
+package pathlib
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"testing"
+	"time"
+
+	"github.com/aisbergg/go-pathlib/internal/testutils"
+	"github.com/spf13/afero"
+)
+
+// collectEvents receives events until no event arrived for the given time.
+func collectEvents(t *testing.T, events <-chan WatchEvent, quiet time.Duration) map[string]WatchOp {
+	ops := map[string]WatchOp{}
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return ops
+			}
+			if event.Err != nil {
+				t.Fatal(event.Err)
+			}
+			ops[event.Path.Name()] |= event.Op
+		case <-time.After(quiet):
+			return ops
+		}
+	}
+}
+
+func testWatch(t *testing.T, root Path, opts *WatchOpts, quiet time.Duration) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	require.NoError(root.Join("existing.txt").WriteFile([]byte("hello")))
+	require.NoError(root.Join("remove.txt").WriteFile([]byte("hello")))
+	require.NoError(root.Join("rename.txt").WriteFile([]byte("hello")))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := root.Watch(ctx, opts)
+	require.NoError(err)
+
+	require.NoError(root.Join("created.txt").WriteFile([]byte("hello")))
+	require.NoError(root.Join("subdir").Mkdir())
+	require.NoError(root.Join("subdir", "nested.txt").WriteFile([]byte("hello")))
+	require.NoError(root.Join("existing.txt").AppendText(" world"))
+	require.NoError(root.Join("remove.txt").Remove())
+	_, err = root.Join("rename.txt").Rename(root.Join("renamed.txt").String())
+	require.NoError(err)
+
+	ops := collectEvents(t, events, quiet)
+	assert.Equal(WatchCreate, ops["created.txt"]&WatchCreate, "created.txt: %v", ops["created.txt"])
+	assert.Equal(WatchCreate, ops["subdir"]&WatchCreate, "subdir: %v", ops["subdir"])
+	assert.Equal(WatchCreate, ops["nested.txt"]&WatchCreate, "nested.txt: %v", ops["nested.txt"])
+	assert.Equal(WatchModify, ops["existing.txt"], "existing.txt: %v", ops["existing.txt"])
+	assert.Equal(WatchRemove, ops["remove.txt"], "remove.txt: %v", ops["remove.txt"])
+	assert.Equal(WatchCreate, ops["renamed.txt"]&WatchCreate, "renamed.txt: %v", ops["renamed.txt"])
+	if ops["rename.txt"] != WatchRename && ops["rename.txt"] != WatchRemove {
+		t.Errorf("rename.txt: unexpected ops %v", ops["rename.txt"])
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestWatch(t *testing.T) {
+	_, _, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	opts := DefaultWatchOpts()
+	opts.PollInterval = 10 * time.Millisecond
+	testWatch(t, tmpdir, opts, 200*time.Millisecond)
+}
+
+func TestWatchPolling(t *testing.T) {
+	_, _, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	opts := DefaultWatchOpts()
+	opts.Polling = true
+	opts.PollInterval = 10 * time.Millisecond
+	testWatch(t, tmpdir, opts, 200*time.Millisecond)
+}
+
+func TestWatchMemMapFs(t *testing.T) {
+	root := NewPathWithFS(afero.NewMemMapFs(), "/root")
+	testutils.NewRequire(t).NoError(root.MkdirAll())
+	opts := DefaultWatchOpts()
+	opts.PollInterval = 10 * time.Millisecond
+	testWatch(t, root, opts, 200*time.Millisecond)
+}
+
+func TestWatchFilter(t *testing.T) {
+	for _, polling := range []bool{false, true} {
+		assert := testutils.NewAssert(t)
+		require := testutils.NewRequire(t)
+		root := tempDirForTest(t, afero.NewOsFs())
+		require.NoError(root.Join("subdir").Mkdir())
+
+		opts := DefaultWatchOpts()
+		opts.Polling = polling
+		opts.PollInterval = 10 * time.Millisecond
+		opts.Recursive = false
+		opts.Filter.VisitDirs = false
+		ctx, cancel := context.WithCancel(context.Background())
+		events, err := root.Watch(ctx, opts)
+		require.NoError(err)
+
+		require.NoError(root.Join("created.txt").WriteFile([]byte("hello")))
+		require.NoError(root.Join("newdir").Mkdir())
+		require.NoError(root.Join("subdir", "nested.txt").WriteFile([]byte("hello")))
+
+		ops := collectEvents(t, events, 200*time.Millisecond)
+		assert.Equal(1, len(ops), "polling %v: %v", polling, ops)
+		assert.Equal(WatchCreate, ops["created.txt"]&WatchCreate, "polling %v: %v", polling, ops)
+		cancel()
+		for range events {
+		}
+	}
+}
+
+func TestWatchDebounce(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	root := tempDirForTest(t, afero.NewOsFs())
+
+	opts := DefaultWatchOpts()
+	opts.Debounce = 100 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := root.Watch(ctx, opts)
+	require.NoError(err)
+
+	file := root.Join("file.txt")
+	require.NoError(file.WriteFile([]byte("hello")))
+	for i := 0; i < 5; i++ {
+		require.NoError(file.AppendText("hello"))
+	}
+	require.NoError(file.Remove())
+
+	select {
+	case event := <-events:
+		assert.Equal(file, event.Path)
+		assert.Equal(WatchCreate|WatchModify|WatchRemove, event.Op)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+	select {
+	case event := <-events:
+		t.Errorf("unexpected event %v", event)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestWatchZeroOpts(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	root := tempDirForTest(t, afero.NewMemMapFs())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := root.Watch(ctx, &WatchOpts{Polling: true})
+	require.NoError(err)
+	require.NoError(root.Join("file.txt").WriteFile([]byte("hello")))
+	ops := collectEvents(t, events, 3*time.Second)
+	assert.Equal(WatchCreate, ops["file.txt"]&WatchCreate)
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestWatchErrors(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	root := tempDirForTest(t, afero.NewOsFs())
+	file := root.Join("file.txt")
+	testutils.NewRequire(t).NoError(file.WriteFile([]byte("hello")))
+
+	_, err := root.Join("i_dont_exist").Watch(context.Background(), DefaultWatchOpts())
+	assert.True(errors.Is(err, fs.ErrNotExist))
+	_, err = file.Watch(context.Background(), DefaultWatchOpts())
+	assert.Error(err)
+	_, err = root.Watch(context.Background(), nil)
+	assert.Error(err)
+}
+
+func TestWatchOpString(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	assert.Equal("CREATE|REMOVE", (WatchCreate | WatchRemove).String())
+	assert.Equal("", WatchOp(0).String())
+}
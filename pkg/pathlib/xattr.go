@@ -0,0 +1,284 @@
+package pathlib
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/afero"
+)
+
+// Xattrer is implemented by afero filesystems that support extended
+// attributes. If follow is false and name is a symlink, the attributes of the
+// symlink itself are used.
+type Xattrer interface {
+	GetXattr(name, attr string, follow bool) ([]byte, error)
+	SetXattr(name, attr string, value []byte, follow bool) error
+	ListXattr(name string, follow bool) ([]string, error)
+	RemoveXattr(name, attr string, follow bool) error
+}
+
+// xattrer returns the Xattrer for the path's filesystem. OsFs is supported on
+// Linux.
+func (p Path) xattrer() (Xattrer, error) {
+	if x, ok := p.Fs().(Xattrer); ok {
+		return x, nil
+	}
+	if isOsFs(p.Fs()) && osXattrs != nil {
+		return osXattrs, nil
+	}
+	return nil, p.pathError("xattr", p.doesNotImplementErr("pathlib.Xattrer"))
+}
+
+// GetXattr returns the value of the extended attribute. Symlinks are followed.
+// An ErrXattrNotFound is returned if the attribute doesn't exist.
+//
+// This will fail if the underlying afero filesystem is neither OsFs on Linux
+// nor implements Xattrer.
+func (p Path) GetXattr(attr string) ([]byte, error) {
+	x, err := p.xattrer()
+	if err != nil {
+		return nil, err
+	}
+	value, err := x.GetXattr(p.String(), attr, true)
+	return value, p.pathError("getxattr", err)
+}
+
+// LGetXattr is the same as GetXattr, but doesn't follow symlinks.
+func (p Path) LGetXattr(attr string) ([]byte, error) {
+	x, err := p.xattrer()
+	if err != nil {
+		return nil, err
+	}
+	value, err := x.GetXattr(p.String(), attr, false)
+	return value, p.pathError("getxattr", err)
+}
+
+// SetXattr sets the value of the extended attribute, creating the attribute if
+// necessary. Symlinks are followed.
+//
+// This will fail if the underlying afero filesystem is neither OsFs on Linux
+// nor implements Xattrer.
+func (p Path) SetXattr(attr string, value []byte) error {
+	x, err := p.xattrer()
+	if err != nil {
+		return err
+	}
+	return p.pathError("setxattr", x.SetXattr(p.String(), attr, value, true))
+}
+
+// LSetXattr is the same as SetXattr, but doesn't follow symlinks.
+func (p Path) LSetXattr(attr string, value []byte) error {
+	x, err := p.xattrer()
+	if err != nil {
+		return err
+	}
+	return p.pathError("setxattr", x.SetXattr(p.String(), attr, value, false))
+}
+
+// ListXattr returns the sorted names of all extended attributes of the file.
+// Symlinks are followed.
+//
+// This will fail if the underlying afero filesystem is neither OsFs on Linux
+// nor implements Xattrer.
+func (p Path) ListXattr() ([]string, error) {
+	x, err := p.xattrer()
+	if err != nil {
+		return nil, err
+	}
+	names, err := x.ListXattr(p.String(), true)
+	return names, p.pathError("listxattr", err)
+}
+
+// LListXattr is the same as ListXattr, but doesn't follow symlinks.
+func (p Path) LListXattr() ([]string, error) {
+	x, err := p.xattrer()
+	if err != nil {
+		return nil, err
+	}
+	names, err := x.ListXattr(p.String(), false)
+	return names, p.pathError("listxattr", err)
+}
+
+// RemoveXattr removes the extended attribute. Symlinks are followed. An
+// ErrXattrNotFound is returned if the attribute doesn't exist.
+//
+// This will fail if the underlying afero filesystem is neither OsFs on Linux
+// nor implements Xattrer.
+func (p Path) RemoveXattr(attr string) error {
+	x, err := p.xattrer()
+	if err != nil {
+		return err
+	}
+	return p.pathError("removexattr", x.RemoveXattr(p.String(), attr, true))
+}
+
+// LRemoveXattr is the same as RemoveXattr, but doesn't follow symlinks.
+func (p Path) LRemoveXattr(attr string) error {
+	x, err := p.xattrer()
+	if err != nil {
+		return err
+	}
+	return p.pathError("removexattr", x.RemoveXattr(p.String(), attr, false))
+}
+
+// xattrNotFoundErr returns the error for a missing attribute, which wraps
+// ErrXattrNotFound.
+func xattrNotFoundErr(op, name, attr string) error {
+	return &os.PathError{Op: op, Path: name, Err: fmt.Errorf("%w: %s", ErrXattrNotFound, attr)}
+}
+
+// -----------------------------------------------------------------------------
+//
+// XattrFs
+//
+// -----------------------------------------------------------------------------
+
+// XattrFs wraps an afero filesystem and keeps extended attributes of its files
+// in memory. It makes code using extended attributes testable on filesystems
+// without support for them, like MemMapFs. Attributes move with their files on
+// Rename and are dropped on Remove and RemoveAll. Symlinks are not
+// distinguished, attributes are always stored on the name itself.
+type XattrFs struct {
+	afero.Fs
+
+	mu    sync.RWMutex
+	attrs map[string]map[string][]byte
+}
+
+// NewXattrFs returns a new XattrFs wrapping the given filesystem.
+func NewXattrFs(fs afero.Fs) *XattrFs {
+	return &XattrFs{
+		Fs:    fs,
+		attrs: map[string]map[string][]byte{},
+	}
+}
+
+// Name returns the name of the filesystem.
+func (fs *XattrFs) Name() string {
+	return "XattrFs(" + getFsName(fs.Fs) + ")"
+}
+
+// normalizeXattrName returns the key of the file in the attribute table.
+func normalizeXattrName(name string) string {
+	return NewPurePath(name).Clean().String()
+}
+
+// GetXattr returns the value of the extended attribute.
+func (fs *XattrFs) GetXattr(name, attr string, follow bool) ([]byte, error) {
+	if _, err := fs.Stat(name); err != nil {
+		return nil, err
+	}
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+	value, ok := fs.attrs[normalizeXattrName(name)][attr]
+	if !ok {
+		return nil, xattrNotFoundErr("getxattr", name, attr)
+	}
+	return append([]byte{}, value...), nil
+}
+
+// SetXattr sets the value of the extended attribute.
+func (fs *XattrFs) SetXattr(name, attr string, value []byte, follow bool) error {
+	if _, err := fs.Stat(name); err != nil {
+		return err
+	}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	key := normalizeXattrName(name)
+	if fs.attrs[key] == nil {
+		fs.attrs[key] = map[string][]byte{}
+	}
+	fs.attrs[key][attr] = append([]byte{}, value...)
+	return nil
+}
+
+// ListXattr returns the sorted names of all extended attributes of the file.
+func (fs *XattrFs) ListXattr(name string, follow bool) ([]string, error) {
+	if _, err := fs.Stat(name); err != nil {
+		return nil, err
+	}
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+	attrs := fs.attrs[normalizeXattrName(name)]
+	names := make([]string, 0, len(attrs))
+	for attr := range attrs {
+		names = append(names, attr)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// RemoveXattr removes the extended attribute.
+func (fs *XattrFs) RemoveXattr(name, attr string, follow bool) error {
+	if _, err := fs.Stat(name); err != nil {
+		return err
+	}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	key := normalizeXattrName(name)
+	if _, ok := fs.attrs[key][attr]; !ok {
+		return xattrNotFoundErr("removexattr", name, attr)
+	}
+	delete(fs.attrs[key], attr)
+	return nil
+}
+
+// Remove removes the file and its extended attributes.
+func (fs *XattrFs) Remove(name string) error {
+	if err := fs.Fs.Remove(name); err != nil {
+		return err
+	}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	delete(fs.attrs, normalizeXattrName(name))
+	return nil
+}
+
+// RemoveAll removes the path, its children and their extended attributes.
+func (fs *XattrFs) RemoveAll(path string) error {
+	if err := fs.Fs.RemoveAll(path); err != nil {
+		return err
+	}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	prefix := normalizeXattrName(path)
+	for key := range fs.attrs {
+		if isXattrKeyBelow(key, prefix) {
+			delete(fs.attrs, key)
+		}
+	}
+	return nil
+}
+
+// Rename renames the file and moves its extended attributes along.
+func (fs *XattrFs) Rename(oldname, newname string) error {
+	if err := fs.Fs.Rename(oldname, newname); err != nil {
+		return err
+	}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	oldPrefix := normalizeXattrName(oldname)
+	newPrefix := normalizeXattrName(newname)
+	moved := map[string]map[string][]byte{}
+	for key, attrs := range fs.attrs {
+		if isXattrKeyBelow(key, oldPrefix) {
+			moved[newPrefix+key[len(oldPrefix):]] = attrs
+			delete(fs.attrs, key)
+		}
+	}
+	for key, attrs := range moved {
+		fs.attrs[key] = attrs
+	}
+	return nil
+}
+
+// isXattrKeyBelow returns whether key equals prefix or is located below it.
+func isXattrKeyBelow(key, prefix string) bool {
+	if key == prefix {
+		return true
+	}
+	return strings.HasPrefix(key, strings.TrimSuffix(prefix, string(os.PathSeparator))+string(os.PathSeparator))
+}
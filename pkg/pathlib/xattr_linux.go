@@ -0,0 +1,138 @@
+package pathlib
+
+import (
+	"bytes"
+	"os"
+	"sort"
+	"syscall"
+	"unsafe"
+)
+
+// osXattrs implements Xattrer for OsFs using the xattr syscalls.
+var osXattrs Xattrer = linuxXattrs{}
+
+type linuxXattrs struct{}
+
+func (linuxXattrs) GetXattr(name, attr string, follow bool) ([]byte, error) {
+	trap := uintptr(syscall.SYS_GETXATTR)
+	if !follow {
+		trap = syscall.SYS_LGETXATTR
+	}
+	namePtr, attrPtr, err := xattrPtrs(name, attr)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		// query the size first, then read into a buffer of that size; retry
+		// if the value grew in between
+		size, _, errno := syscall.Syscall6(trap, uintptr(unsafe.Pointer(namePtr)), uintptr(unsafe.Pointer(attrPtr)), 0, 0, 0, 0)
+		if errno != 0 {
+			return nil, xattrErr("getxattr", name, attr, errno)
+		}
+		buf := make([]byte, size)
+		if size == 0 {
+			return buf, nil
+		}
+		n, _, errno := syscall.Syscall6(trap, uintptr(unsafe.Pointer(namePtr)), uintptr(unsafe.Pointer(attrPtr)), uintptr(unsafe.Pointer(&buf[0])), size, 0, 0)
+		if errno == syscall.ERANGE {
+			continue
+		}
+		if errno != 0 {
+			return nil, xattrErr("getxattr", name, attr, errno)
+		}
+		return buf[:n], nil
+	}
+}
+
+func (linuxXattrs) SetXattr(name, attr string, value []byte, follow bool) error {
+	trap := uintptr(syscall.SYS_SETXATTR)
+	if !follow {
+		trap = syscall.SYS_LSETXATTR
+	}
+	namePtr, attrPtr, err := xattrPtrs(name, attr)
+	if err != nil {
+		return err
+	}
+	var valuePtr unsafe.Pointer
+	if len(value) > 0 {
+		valuePtr = unsafe.Pointer(&value[0])
+	}
+	_, _, errno := syscall.Syscall6(trap, uintptr(unsafe.Pointer(namePtr)), uintptr(unsafe.Pointer(attrPtr)), uintptr(valuePtr), uintptr(len(value)), 0, 0)
+	if errno != 0 {
+		return xattrErr("setxattr", name, attr, errno)
+	}
+	return nil
+}
+
+func (linuxXattrs) ListXattr(name string, follow bool) ([]string, error) {
+	trap := uintptr(syscall.SYS_LISTXATTR)
+	if !follow {
+		trap = syscall.SYS_LLISTXATTR
+	}
+	namePtr, err := syscall.BytePtrFromString(name)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		size, _, errno := syscall.Syscall(trap, uintptr(unsafe.Pointer(namePtr)), 0, 0)
+		if errno != 0 {
+			return nil, xattrErr("listxattr", name, "", errno)
+		}
+		if size == 0 {
+			return []string{}, nil
+		}
+		buf := make([]byte, size)
+		n, _, errno := syscall.Syscall(trap, uintptr(unsafe.Pointer(namePtr)), uintptr(unsafe.Pointer(&buf[0])), size)
+		if errno == syscall.ERANGE {
+			continue
+		}
+		if errno != 0 {
+			return nil, xattrErr("listxattr", name, "", errno)
+		}
+		var names []string
+		for _, attr := range bytes.Split(buf[:n], []byte{0}) {
+			if len(attr) > 0 {
+				names = append(names, string(attr))
+			}
+		}
+		sort.Strings(names)
+		return names, nil
+	}
+}
+
+func (linuxXattrs) RemoveXattr(name, attr string, follow bool) error {
+	trap := uintptr(syscall.SYS_REMOVEXATTR)
+	if !follow {
+		trap = syscall.SYS_LREMOVEXATTR
+	}
+	namePtr, attrPtr, err := xattrPtrs(name, attr)
+	if err != nil {
+		return err
+	}
+	_, _, errno := syscall.Syscall(trap, uintptr(unsafe.Pointer(namePtr)), uintptr(unsafe.Pointer(attrPtr)), 0)
+	if errno != 0 {
+		return xattrErr("removexattr", name, attr, errno)
+	}
+	return nil
+}
+
+func xattrPtrs(name, attr string) (*byte, *byte, error) {
+	namePtr, err := syscall.BytePtrFromString(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	attrPtr, err := syscall.BytePtrFromString(attr)
+	if err != nil {
+		return nil, nil, err
+	}
+	return namePtr, attrPtr, nil
+}
+
+// xattrErr converts the errno of a xattr syscall into an error. A missing
+// attribute results in an ErrXattrNotFound.
+func xattrErr(op, name, attr string, errno syscall.Errno) error {
+	if errno == syscall.ENODATA {
+		return xattrNotFoundErr(op, name, attr)
+	}
+	return &os.PathError{Op: op, Path: name, Err: errno}
+}
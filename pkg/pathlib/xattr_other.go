@@ -0,0 +1,8 @@
+//go:build !linux
+// +build !linux
+
+package pathlib
+
+// osXattrs is nil as extended attributes of OsFs are not supported on this
+// platform.
+var osXattrs Xattrer
@@ -0,0 +1,104 @@
+package pathlib
+
+import (
+	"errors"
+	"io/fs"
+	"runtime"
+	"syscall"
+	"testing"
+
+	"github.com/aisbergg/go-pathlib/internal/testutils"
+	"github.com/spf13/afero"
+)
+
+func testXattrs(t *testing.T, file Path) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+
+	names, err := file.ListXattr()
+	require.NoError(err)
+	assert.Equal(0, len(names))
+
+	_, err = file.GetXattr("user.provenance")
+	assert.EqualError(ErrXattrNotFound, err)
+
+	require.NoError(file.SetXattr("user.provenance", []byte("build 42")))
+	require.NoError(file.SetXattr("user.empty", []byte{}))
+	value, err := file.GetXattr("user.provenance")
+	require.NoError(err)
+	assert.Equal([]byte("build 42"), value)
+	value, err = file.LGetXattr("user.empty")
+	require.NoError(err)
+	assert.Equal([]byte{}, value)
+
+	require.NoError(file.SetXattr("user.provenance", []byte("build 43")))
+	value, err = file.GetXattr("user.provenance")
+	require.NoError(err)
+	assert.Equal([]byte("build 43"), value)
+
+	names, err = file.ListXattr()
+	require.NoError(err)
+	assert.Equal([]string{"user.empty", "user.provenance"}, names)
+
+	require.NoError(file.RemoveXattr("user.empty"))
+	assert.EqualError(ErrXattrNotFound, file.RemoveXattr("user.empty"))
+	names, err = file.LListXattr()
+	require.NoError(err)
+	assert.Equal([]string{"user.provenance"}, names)
+
+	_, err = file.Parent().Join("i_dont_exist").GetXattr("user.provenance")
+	assert.True(errors.Is(err, fs.ErrNotExist))
+}
+
+func TestXattrOsFs(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("extended attributes of OsFs are only supported on Linux")
+	}
+	_, require, tmpdir := setupPathTest(t)
+	defer teardownPathTest(t, tmpdir)
+	file := tmpdir.Join("file.txt")
+	require.NoError(file.WriteFile([]byte("hello")))
+	if err := file.SetXattr("user.probe", []byte("1")); errors.Is(err, syscall.ENOTSUP) {
+		t.Skip("filesystem of the temporary directory does not support user xattrs")
+	}
+	require.NoError(file.RemoveXattr("user.probe"))
+	testXattrs(t, file)
+}
+
+func TestXattrFs(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	fs := NewXattrFs(afero.NewMemMapFs())
+	file := NewPathWithFS(fs, "/dir/file.txt")
+	require.NoError(file.Parent().MkdirAll())
+	require.NoError(file.WriteFile([]byte("hello")))
+	testXattrs(t, file)
+
+	// attributes move along with renames
+	renamed, err := file.RenamePath(NewPathWithFS(fs, "/dir/renamed.txt"))
+	require.NoError(err)
+	value, err := renamed.GetXattr("user.provenance")
+	require.NoError(err)
+	assert.Equal([]byte("build 43"), value)
+
+	// and are dropped on removal
+	require.NoError(renamed.Parent().RemoveAll())
+	require.NoError(renamed.Parent().MkdirAll())
+	require.NoError(renamed.WriteFile([]byte("hello")))
+	_, err = renamed.GetXattr("user.provenance")
+	assert.EqualError(ErrXattrNotFound, err)
+}
+
+func TestXattrNotImplemented(t *testing.T) {
+	assert := testutils.NewAssert(t)
+	require := testutils.NewRequire(t)
+	file := NewPathWithFS(afero.NewMemMapFs(), "/file.txt")
+	require.NoError(file.WriteFile([]byte("hello")))
+
+	_, err := file.GetXattr("user.provenance")
+	assert.True(errors.Is(err, ErrDoesNotImplement))
+	assert.True(errors.Is(file.SetXattr("user.provenance", nil), ErrDoesNotImplement))
+	_, err = file.ListXattr()
+	assert.True(errors.Is(err, ErrDoesNotImplement))
+	assert.True(errors.Is(file.RemoveXattr("user.provenance"), ErrDoesNotImplement))
+}